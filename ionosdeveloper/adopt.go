@@ -0,0 +1,112 @@
+package ionosdeveloper
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+
+	dnsSdk "github.com/ionos-developer/dns-sdk-go"
+)
+
+// Values of the on_conflict argument of ionosdeveloper_dns_record
+const (
+	onConflictError     = "error"
+	onConflictAdopt     = "adopt"
+	onConflictOverwrite = "overwrite"
+)
+
+// findConflictingRecord returns the existing record that a new record would conflict with, and whether it
+// has the same content. A record with the same name, type and content always conflicts. If overwrite is set,
+// the only record with the same name and type conflicts as well, e.g. the CNAME record of the name. The
+// record is normalized by the API first, so that e.g. the quoting of TXT records does not prevent a match.
+func findConflictingRecord(ctx context.Context, m interface{}, zoneId string, record *dnsSdk.Record, overwrite bool) (*dnsSdk.RecordResponse, bool, error) {
+	client := m.(SdkBundle).DnsApiClient
+
+	normalized, _, err := client.RecordsApi.NormalizeRecord(ctx).Record(*record).Execute()
+	if err != nil {
+		return nil, false, err
+	}
+
+	// The recordName filter matches all names ending with the name
+	zone, _, err := client.ZonesApi.GetZone(ctx, zoneId).RecordName(normalized.GetName()).RecordType(string(normalized.GetType())).Execute()
+	if err != nil {
+		return nil, false, err
+	}
+
+	var sameType []dnsSdk.RecordResponse
+	for _, existing := range zone.GetRecords() {
+		if !strings.EqualFold(existing.GetName(), normalized.GetName()) || existing.GetType() != normalized.GetType() {
+			continue
+		}
+		if existing.GetContent() == normalized.GetContent() {
+			return &existing, true, nil
+		}
+		sameType = append(sameType, existing)
+	}
+
+	switch {
+	case !overwrite || len(sameType) == 0:
+		return nil, false, nil
+	case len(sameType) == 1:
+		return &sameType[0], false, nil
+	default:
+		return nil, false, fmt.Errorf("%d %s records named %s exist, on_conflict = %q can only replace a single record",
+			len(sameType), normalized.GetType(), normalized.GetName(), onConflictOverwrite)
+	}
+}
+
+// adoptDnsRecord manages an existing record instead of creating a new one. Its ttl, prio and disabled, and
+// its content unless sameContent is set, are updated in place if they differ from the record to create.
+func adoptDnsRecord(ctx context.Context, m interface{}, zoneId string, onConflict string, existing *dnsSdk.RecordResponse, sameContent bool, record *dnsSdk.Record) (*dnsSdk.RecordResponse, diag.Diagnostics) {
+	tflog.Warn(ctx, "Adopting existing DNS record", map[string]interface{}{
+		"id":          existing.GetId(),
+		"name":        existing.GetName(),
+		"type":        string(existing.GetType()),
+		"on_conflict": onConflict,
+	})
+
+	diags := diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  "Adopted existing DNS record",
+		Detail: fmt.Sprintf("The %s record %s (%s) already existed and is managed by this resource now, because on_conflict is set to %q.",
+			existing.GetType(), existing.GetName(), existing.GetId(), onConflict),
+	}}
+
+	// Adopting a record takes over the ownership of its name and type, unless it is owned by another owner_id
+	unlock := m.(SdkBundle).Ownership.lock(zoneId, ownershipMarkerName(record.GetName(), record.GetType()))
+	err := claimRecordOwnership(ctx, m, zoneId, record, true)
+	unlock()
+	if err != nil {
+		return nil, appendError(diags, "Unable to claim the ownership of the record", err)
+	}
+
+	recordUpdate := dnsSdk.NewRecordUpdate()
+	changed := false
+	if !sameContent {
+		recordUpdate.SetContent(record.GetContent())
+		changed = true
+	}
+	if record.Ttl != nil && existing.GetTtl() != record.GetTtl() {
+		recordUpdate.SetTtl(record.GetTtl())
+		changed = true
+	}
+	if record.Prio != nil && existing.GetPrio() != record.GetPrio() {
+		recordUpdate.SetPrio(record.GetPrio())
+		changed = true
+	}
+	if existing.GetDisabled() != record.GetDisabled() {
+		recordUpdate.SetDisabled(record.GetDisabled())
+		changed = true
+	}
+
+	if !changed {
+		return existing, diags
+	}
+
+	// The record has not been adopted if the update fails
+	updatedRecord, updateDiags := updateDnsRecord(ctx, m, zoneId, existing.GetId(), *recordUpdate)
+	return updatedRecord, append(diags, updateDiags...)
+}
@@ -2,8 +2,10 @@ package ionosdeveloper
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 
+	fwdiag "github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	dnsSdk "github.com/ionos-developer/dns-sdk-go"
 )
@@ -22,9 +24,34 @@ func getIndentedBody(err error) string {
 }
 
 func appendError(diags diag.Diagnostics, summary string, err error) diag.Diagnostics {
+	detail := getIndentedBody(err)
+	// Errors that do not come from an API response, e.g. timeouts, have no body
+	if _, ok := err.(*dnsSdk.GenericOpenAPIError); !ok && err != nil {
+		detail = err.Error()
+	}
+	// A read-only provider describes the request instead, without the URL prefix of the HTTP client
+	var readOnly *readOnlyError
+	if errors.As(err, &readOnly) {
+		detail = readOnly.Error()
+	}
+
 	return append(diags, diag.Diagnostic{
 		Severity: diag.Error,
 		Summary:  summary,
-		Detail:   fmt.Sprintf("%v\n", getIndentedBody(err)),
+		Detail:   fmt.Sprintf("%v\n", detail),
 	})
 }
+
+// frameworkDiagnostics converts the diagnostics of the functions shared by the SDKv2 and the
+// terraform-plugin-framework resources
+func frameworkDiagnostics(diags diag.Diagnostics) fwdiag.Diagnostics {
+	var converted fwdiag.Diagnostics
+	for _, d := range diags {
+		if d.Severity == diag.Error {
+			converted.AddError(d.Summary, d.Detail)
+		} else {
+			converted.AddWarning(d.Summary, d.Detail)
+		}
+	}
+	return converted
+}
@@ -0,0 +1,282 @@
+package ionosdeveloper
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	dnsSdk "github.com/ionos-developer/dns-sdk-go"
+)
+
+const (
+	defaultCreateBatchWindow = 50 * time.Millisecond
+
+	// maxCreateBatchSize keeps the request bodies reasonably small, a full batch is sent immediately
+	maxCreateBatchSize = 100
+)
+
+// recordBatcher coalesces the records created concurrently in the same zone into a single CreateRecords
+// request. Terraform creates independent resources in parallel, so the records of an apply arrive within
+// a few milliseconds of each other. The first record of a zone opens a batch that is sent when the window
+// has passed or the batch is full.
+type recordBatcher struct {
+	client  *dnsSdk.APIClient
+	window  time.Duration
+	maxSize int
+
+	mu      sync.Mutex
+	pending map[string]*recordBatch
+}
+
+type recordBatch struct {
+	// ctx is the context of the first record without its cancellation
+	ctx     context.Context
+	zoneId  string
+	entries []*recordBatchEntry
+}
+
+type recordBatchEntry struct {
+	record dnsSdk.Record
+	result chan recordBatchResult
+	// abandoned is set once the caller has left after the batch was sent, it is guarded by recordBatcher.mu
+	abandoned bool
+}
+
+type recordBatchResult struct {
+	record dnsSdk.RecordResponse
+	err    error
+}
+
+// newRecordBatcher returns a batcher that sends every record on its own if window is 0
+func newRecordBatcher(client *dnsSdk.APIClient, window time.Duration) *recordBatcher {
+	return &recordBatcher{client: client, window: window, maxSize: maxCreateBatchSize, pending: map[string]*recordBatch{}}
+}
+
+// create creates a record, possibly together with records of other resources, and returns the created
+// record. If the batch is rejected, each of its records is created on its own, so that the error is
+// returned for the records that caused it only.
+func (b *recordBatcher) create(ctx context.Context, zoneId string, record dnsSdk.Record) (dnsSdk.RecordResponse, error) {
+	if b.window <= 0 {
+		return b.createSingle(ctx, zoneId, record)
+	}
+
+	entry := &recordBatchEntry{record: record, result: make(chan recordBatchResult, 1)}
+
+	b.mu.Lock()
+	batch := b.pending[zoneId]
+	if batch == nil {
+		// SDKv2 cancels the context of a resource once it is created, while the batch may still be sent
+		batch = &recordBatch{ctx: context.WithoutCancel(ctx), zoneId: zoneId}
+		b.pending[zoneId] = batch
+		time.AfterFunc(b.window, func() { b.flush(batch) })
+	}
+	batch.entries = append(batch.entries, entry)
+	full := len(batch.entries) >= b.maxSize
+	if full {
+		// The next record opens a new batch, so that a batch never exceeds maxSize
+		delete(b.pending, zoneId)
+	}
+	b.mu.Unlock()
+
+	if full {
+		go b.send(batch)
+	}
+
+	select {
+	case r := <-entry.result:
+		return r.record, r.err
+	case <-ctx.Done():
+		return b.abandon(batch, entry, ctx.Err())
+	}
+}
+
+// abandon removes the record of a caller that has left from its batch. If the batch has already been sent,
+// the record is deleted once it has been created, since it would never be part of the state.
+func (b *recordBatcher) abandon(batch *recordBatch, entry *recordBatchEntry, err error) (dnsSdk.RecordResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.pending[batch.zoneId] == batch {
+		for i, other := range batch.entries {
+			if other == entry {
+				batch.entries = append(batch.entries[:i], batch.entries[i+1:]...)
+				break
+			}
+		}
+		if len(batch.entries) == 0 {
+			delete(b.pending, batch.zoneId)
+		}
+		return dnsSdk.RecordResponse{}, err
+	}
+
+	select {
+	case r := <-entry.result:
+		// The result has been delivered in the meantime
+		return r.record, r.err
+	default:
+		entry.abandoned = true
+		return dnsSdk.RecordResponse{}, err
+	}
+}
+
+// flush sends a batch when its window has passed, unless it has already been sent because it was full
+func (b *recordBatcher) flush(batch *recordBatch) {
+	b.mu.Lock()
+	if b.pending[batch.zoneId] != batch {
+		b.mu.Unlock()
+		return
+	}
+	delete(b.pending, batch.zoneId)
+	b.mu.Unlock()
+
+	b.send(batch)
+}
+
+// send creates the records of a batch that has been removed from pending
+func (b *recordBatcher) send(batch *recordBatch) {
+	// The callers of a full batch may leave before it is sent
+	b.mu.Lock()
+	var entries []*recordBatchEntry
+	for _, entry := range batch.entries {
+		if !entry.abandoned {
+			entries = append(entries, entry)
+		}
+	}
+	batch.entries = entries
+	b.mu.Unlock()
+	if len(batch.entries) == 0 {
+		return
+	}
+
+	records := make([]dnsSdk.Record, len(batch.entries))
+	for i, entry := range batch.entries {
+		records[i] = entry.record
+	}
+
+	created, _, err := b.client.RecordsApi.CreateRecords(batch.ctx, batch.zoneId).Record(records).Execute()
+	switch {
+	case err == nil:
+		// The API does not document the order of the created records, they are matched by their values
+		matches, orphans := matchCreatedRecords(records, created)
+		// The records that cannot be attributed to a resource would never be part of the state, they are
+		// deleted before the results are returned since Terraform may stop the provider afterwards
+		for _, orphan := range orphans {
+			b.deleteCreated(batch, orphan, "Unable to delete a created record that could not be matched to its resource")
+		}
+		for i, match := range matches {
+			if match == nil {
+				err := fmt.Errorf("the API did not return the created record %s %s", records[i].GetName(), records[i].GetType())
+				b.deliver(batch, batch.entries[i], recordBatchResult{err: err})
+				continue
+			}
+			b.deliver(batch, batch.entries[i], recordBatchResult{record: *match})
+		}
+	case len(batch.entries) == 1:
+		b.deliver(batch, batch.entries[0], recordBatchResult{err: err})
+	default:
+		// The API rejects the whole batch if one of the records is invalid, which would otherwise fail
+		// every resource of the batch
+		for _, entry := range batch.entries {
+			b.mu.Lock()
+			abandoned := entry.abandoned
+			b.mu.Unlock()
+			if abandoned {
+				continue
+			}
+
+			created, err := b.createSingle(batch.ctx, batch.zoneId, entry.record)
+			b.deliver(batch, entry, recordBatchResult{record: created, err: err})
+		}
+	}
+}
+
+// deliver returns the result to the caller, or deletes the created record if the caller has left
+func (b *recordBatcher) deliver(batch *recordBatch, entry *recordBatchEntry, result recordBatchResult) {
+	b.mu.Lock()
+	abandoned := entry.abandoned
+	if !abandoned {
+		entry.result <- result
+	}
+	b.mu.Unlock()
+
+	if !abandoned || result.err != nil {
+		return
+	}
+
+	b.deleteCreated(batch, result.record, "Unable to delete a record created after its resource was cancelled")
+}
+
+// deleteCreated deletes a created record that no resource will manage, a failure is only logged
+func (b *recordBatcher) deleteCreated(batch *recordBatch, record dnsSdk.RecordResponse, message string) {
+	_, err := b.client.RecordsApi.DeleteRecord(batch.ctx, batch.zoneId, record.GetId()).Execute()
+	if err != nil {
+		tflog.Warn(batch.ctx, message, map[string]interface{}{
+			"name":  record.GetName(),
+			"id":    record.GetId(),
+			"error": err.Error(),
+		})
+	}
+}
+
+func (b *recordBatcher) createSingle(ctx context.Context, zoneId string, record dnsSdk.Record) (dnsSdk.RecordResponse, error) {
+	created, _, err := b.client.RecordsApi.CreateRecords(ctx, zoneId).Record([]dnsSdk.Record{record}).Execute()
+	if err != nil {
+		return dnsSdk.RecordResponse{}, err
+	}
+	if len(created) != 1 {
+		return dnsSdk.RecordResponse{}, fmt.Errorf("the API returned %d records for a single record", len(created))
+	}
+
+	return created[0], nil
+}
+
+// matchCreatedRecords returns the created record of each record, or nil if the API did not return it, and
+// the created records that could not be matched. The API may normalize a value in a way createdRecordMatches
+// does not know, so if as many records are left on both sides, they are matched in the order of the response.
+func matchCreatedRecords(records []dnsSdk.Record, created []dnsSdk.RecordResponse) ([]*dnsSdk.RecordResponse, []dnsSdk.RecordResponse) {
+	matches := make([]*dnsSdk.RecordResponse, len(records))
+	used := make([]bool, len(created))
+	var unmatched []int
+	for i, record := range records {
+		for j := range created {
+			if !used[j] && createdRecordMatches(record, created[j]) {
+				used[j] = true
+				matches[i] = &created[j]
+				break
+			}
+		}
+		if matches[i] == nil {
+			unmatched = append(unmatched, i)
+		}
+	}
+
+	var orphans []int
+	for j := range created {
+		if !used[j] {
+			orphans = append(orphans, j)
+		}
+	}
+	if len(unmatched) == len(orphans) {
+		for k, i := range unmatched {
+			matches[i] = &created[orphans[k]]
+		}
+		return matches, nil
+	}
+
+	orphanRecords := make([]dnsSdk.RecordResponse, len(orphans))
+	for k, j := range orphans {
+		orphanRecords[k] = created[j]
+	}
+	return matches, orphanRecords
+}
+
+func createdRecordMatches(record dnsSdk.Record, created dnsSdk.RecordResponse) bool {
+	recordType := record.GetType()
+	return comparableRecordName(record.GetName()) == comparableRecordName(created.GetName()) &&
+		recordType == created.GetType() &&
+		comparableRecordContent(recordType, record.GetContent()) == comparableRecordContent(recordType, created.GetContent()) &&
+		(record.Prio == nil || record.GetPrio() == created.GetPrio())
+}
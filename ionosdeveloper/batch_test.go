@@ -0,0 +1,415 @@
+package ionosdeveloper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	dnsSdk "github.com/ionos-developer/dns-sdk-go"
+)
+
+func TestRecordBatcher_Coalesce(t *testing.T) {
+	api := newFakeDnsApi()
+	defer api.Close()
+
+	zoneId := api.AddZone("example.com")
+	otherZoneId := api.AddZone("example.org")
+	batcher := newRecordBatcher(newDnsApiClient(api.URL(), defaultAuthHeader, fakeApiKey, "test", nil), time.Second)
+
+	records := []struct {
+		zoneId string
+		name   string
+	}{
+		{zoneId, "a.example.com"},
+		{zoneId, "b.example.com"},
+		{zoneId, "c.example.com"},
+		{otherZoneId, "a.example.org"},
+	}
+
+	created := make([]dnsSdk.RecordResponse, len(records))
+	errs := make([]error, len(records))
+	var wg sync.WaitGroup
+	for i, r := range records {
+		wg.Add(1)
+		go func(i int, zoneId, name string) {
+			defer wg.Done()
+			created[i], errs[i] = batcher.create(context.Background(), zoneId, testBatchRecord(name, 3600))
+		}(i, r.zoneId, r.name)
+	}
+	wg.Wait()
+
+	for i, r := range records {
+		if errs[i] != nil {
+			t.Fatalf("unexpected error for %s: %s", r.name, errs[i])
+		}
+		if created[i].GetName() != r.name || created[i].GetId() == "" {
+			t.Errorf("expected the created record %s, got %s (%s)", r.name, created[i].GetName(), created[i].GetId())
+		}
+	}
+	if calls := api.Calls("CreateRecords"); calls != 2 {
+		t.Errorf("expected one CreateRecords request per zone, got %d", calls)
+	}
+}
+
+func TestRecordBatcher_FullBatch(t *testing.T) {
+	api := newFakeDnsApi()
+	defer api.Close()
+
+	zoneId := api.AddZone("example.com")
+	batcher := newRecordBatcher(newDnsApiClient(api.URL(), defaultAuthHeader, fakeApiKey, "test", nil), time.Hour)
+	batcher.maxSize = 2
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, err := batcher.create(context.Background(), zoneId, testBatchRecord(fmt.Sprintf("r%d.example.com", i), 3600)); err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if calls := api.Calls("CreateRecords"); calls != 2 {
+		t.Errorf("expected full batches to be sent without waiting, got %d requests", calls)
+	}
+}
+
+func TestRecordBatcher_Latency(t *testing.T) {
+	api := newFakeDnsApi()
+	defer api.Close()
+
+	zoneId := api.AddZone("example.com")
+	api.SetLatency(300 * time.Millisecond)
+	batcher := newRecordBatcher(newDnsApiClient(api.URL(), defaultAuthHeader, fakeApiKey, "test", nil), 50*time.Millisecond)
+
+	// The records created while the first batch is sent are collected in the next batch
+	names := []string{"a.example.com", "b.example.com", "c.example.com", "d.example.com"}
+	created := make([]dnsSdk.RecordResponse, len(names))
+	errs := make([]error, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		if i == 1 {
+			time.Sleep(150 * time.Millisecond)
+		}
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			created[i], errs[i] = batcher.create(context.Background(), zoneId, testBatchRecord(name, 3600))
+		}(i, name)
+	}
+	wg.Wait()
+
+	for i, name := range names {
+		if errs[i] != nil {
+			t.Fatalf("unexpected error for %s: %s", name, errs[i])
+		}
+		if created[i].GetName() != name {
+			t.Errorf("expected the created record %s, got %s", name, created[i].GetName())
+		}
+	}
+	if calls := api.Calls("CreateRecords"); calls != 2 {
+		t.Errorf("expected 2 CreateRecords requests, got %d", calls)
+	}
+}
+
+func TestRecordBatcher_ErrorAttribution(t *testing.T) {
+	api := newFakeDnsApi()
+	defer api.Close()
+
+	zoneId := api.AddZone("example.com")
+	batcher := newRecordBatcher(newDnsApiClient(api.URL(), defaultAuthHeader, fakeApiKey, "test", nil), time.Second)
+
+	ttls := []int32{3600, 30, 3600}
+	errs := make([]error, len(ttls))
+	var wg sync.WaitGroup
+	for i, ttl := range ttls {
+		wg.Add(1)
+		go func(i int, ttl int32) {
+			defer wg.Done()
+			_, errs[i] = batcher.create(context.Background(), zoneId, testBatchRecord(fmt.Sprintf("r%d.example.com", i), ttl))
+		}(i, ttl)
+	}
+	wg.Wait()
+
+	for i, ttl := range ttls {
+		if valid := ttl >= 60; valid != (errs[i] == nil) {
+			t.Errorf("record %d with ttl %d: unexpected error %v", i, ttl, errs[i])
+		}
+	}
+	if errs[1] != nil && !strings.Contains(string(errs[1].(*dnsSdk.GenericOpenAPIError).Body()), "TTL must be at least 60") {
+		t.Errorf("expected the error of the API, got %s", errs[1].(*dnsSdk.GenericOpenAPIError).Body())
+	}
+	if records := api.Records(zoneId); len(records) != 2 {
+		t.Errorf("expected the valid records to be created, got %d records", len(records))
+	}
+	// The rejected batch and one request per record
+	if calls := api.Calls("CreateRecords"); calls != 4 {
+		t.Errorf("expected 4 CreateRecords requests, got %d", calls)
+	}
+}
+
+func TestRecordBatcher_Cancel(t *testing.T) {
+	t.Run("before sending", func(t *testing.T) {
+		api := newFakeDnsApi()
+		defer api.Close()
+
+		zoneId := api.AddZone("example.com")
+		batcher := newRecordBatcher(newDnsApiClient(api.URL(), defaultAuthHeader, fakeApiKey, "test", nil), 200*time.Millisecond)
+
+		// The record opening the batch leaves before the batch is sent
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		var cancelledErr error
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, cancelledErr = batcher.create(ctx, zoneId, testBatchRecord("a.example.com", 3600))
+		}()
+		time.Sleep(10 * time.Millisecond)
+
+		created, err := batcher.create(context.Background(), zoneId, testBatchRecord("b.example.com", 3600))
+		wg.Wait()
+
+		if err != nil || created.GetName() != "b.example.com" {
+			t.Errorf("expected the record b.example.com to be created, got %s: %v", created.GetName(), err)
+		}
+		if cancelledErr != context.DeadlineExceeded {
+			t.Errorf("expected the cancelled record to fail with %s, got %v", context.DeadlineExceeded, cancelledErr)
+		}
+		if records := api.Records(zoneId); len(records) != 1 || records[0].GetName() != "b.example.com" {
+			t.Errorf("expected the record b.example.com only, got %d records", len(records))
+		}
+	})
+
+	t.Run("after returning", func(t *testing.T) {
+		api := newFakeDnsApi()
+		defer api.Close()
+
+		zoneId := api.AddZone("example.com")
+		batcher := newRecordBatcher(newDnsApiClient(api.URL(), defaultAuthHeader, fakeApiKey, "test", nil), 100*time.Millisecond)
+
+		// Like SDKv2, every caller cancels its context once it returns. The first record opens the batch
+		// and is created first when the rejected batch falls back to single requests.
+		ttls := []int32{3600, 30, 3600}
+		errs := make([]error, len(ttls))
+		var wg sync.WaitGroup
+		for i, ttl := range ttls {
+			wg.Add(1)
+			go func(i int, ttl int32) {
+				defer wg.Done()
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				_, errs[i] = batcher.create(ctx, zoneId, testBatchRecord(fmt.Sprintf("r%d.example.com", i), ttl))
+			}(i, ttl)
+			time.Sleep(10 * time.Millisecond)
+		}
+		wg.Wait()
+
+		if errs[0] != nil || errs[1] == nil || errs[2] != nil {
+			t.Errorf("expected the invalid record to fail only, got %v", errs)
+		}
+	})
+
+	t.Run("while sending", func(t *testing.T) {
+		api := newFakeDnsApi()
+		defer api.Close()
+
+		zoneId := api.AddZone("example.com")
+		api.SetLatency(200 * time.Millisecond)
+		batcher := newRecordBatcher(newDnsApiClient(api.URL(), defaultAuthHeader, fakeApiKey, "test", nil), 10*time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		if _, err := batcher.create(ctx, zoneId, testBatchRecord("a.example.com", 3600)); err != context.DeadlineExceeded {
+			t.Fatalf("expected %s, got %v", context.DeadlineExceeded, err)
+		}
+
+		// The record is created after its caller has left, and deleted again
+		deadline := time.Now().Add(2 * time.Second)
+		for api.Calls("DeleteRecord") == 0 && time.Now().Before(deadline) {
+			time.Sleep(50 * time.Millisecond)
+		}
+		if calls := api.Calls("CreateRecords"); calls != 1 {
+			t.Errorf("expected 1 CreateRecords request, got %d", calls)
+		}
+		if records := api.Records(zoneId); len(records) != 0 {
+			t.Errorf("expected the abandoned record to be deleted, got %d records", len(records))
+		}
+	})
+}
+
+func TestRecordBatcher_UnmatchedRecords(t *testing.T) {
+	// createRecords sends two records in a batch to an API whose CreateRecords responses are rewritten by
+	// rewrite, and returns the created records and errors
+	createRecords := func(t *testing.T, api *fakeDnsApi, zoneId string, rewrite func([]dnsSdk.RecordResponse) []dnsSdk.RecordResponse) ([]dnsSdk.RecordResponse, []error) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if operation, _ := fakeRoute(r.Method, r.URL.Path); operation != "CreateRecords" {
+				api.ServeHTTP(w, r)
+				return
+			}
+
+			recorder := httptest.NewRecorder()
+			api.ServeHTTP(recorder, r)
+			var created []dnsSdk.RecordResponse
+			if err := json.Unmarshal(recorder.Body.Bytes(), &created); err != nil {
+				t.Errorf("unexpected response %s", recorder.Body.String())
+			}
+			writeFakeJson(w, recorder.Code, rewrite(created))
+		}))
+		defer server.Close()
+
+		batcher := newRecordBatcher(newDnsApiClient(server.URL, defaultAuthHeader, fakeApiKey, "test", nil), time.Second)
+		created := make([]dnsSdk.RecordResponse, 2)
+		errs := make([]error, 2)
+		var wg sync.WaitGroup
+		for i := range created {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				created[i], errs[i] = batcher.create(context.Background(), zoneId, testBatchRecord(fmt.Sprintf("r%d.example.com", i), 3600))
+			}(i)
+		}
+		wg.Wait()
+
+		return created, errs
+	}
+
+	t.Run("normalized", func(t *testing.T) {
+		api := newFakeDnsApi()
+		defer api.Close()
+
+		zoneId := api.AddZone("example.com")
+		created, errs := createRecords(t, api, zoneId, func(created []dnsSdk.RecordResponse) []dnsSdk.RecordResponse {
+			for i := range created {
+				created[i].SetContent("192.0.2.99")
+			}
+			return created
+		})
+
+		for i := range created {
+			if errs[i] != nil {
+				t.Fatalf("unexpected error for record %d: %s", i, errs[i])
+			}
+		}
+		if created[0].GetId() == "" || created[0].GetId() == created[1].GetId() {
+			t.Errorf("expected a created record per resource, got %q and %q", created[0].GetId(), created[1].GetId())
+		}
+	})
+
+	t.Run("orphaned", func(t *testing.T) {
+		api := newFakeDnsApi()
+		defer api.Close()
+
+		zoneId := api.AddZone("example.com")
+		_, errs := createRecords(t, api, zoneId, func(created []dnsSdk.RecordResponse) []dnsSdk.RecordResponse {
+			created[0].SetContent("192.0.2.99")
+			return created[:1]
+		})
+
+		for i := range errs {
+			if errs[i] == nil || !strings.Contains(errs[i].Error(), "did not return the created record") {
+				t.Errorf("expected an error for record %d, got %v", i, errs[i])
+			}
+		}
+		// The record the API did not return is unknown to the provider
+		if records := api.Records(zoneId); len(records) != 1 {
+			t.Errorf("expected the orphaned record to be deleted, got %d records", len(records))
+		}
+		if calls := api.Calls("DeleteRecord"); calls != 1 {
+			t.Errorf("expected 1 DeleteRecord request, got %d", calls)
+		}
+	})
+}
+
+func TestMatchCreatedRecords(t *testing.T) {
+	records := []dnsSdk.Record{
+		testBatchRecord("a.example.com", 3600),
+		testBatchRecord("b.example.com", 3600),
+		{Name: dnsSdk.PtrString("txt.example.com"), Type: recordTypePtr(dnsSdk.TXT), Content: dnsSdk.PtrString("text")},
+		testBatchRecord("missing.example.com", 3600),
+	}
+	// The API may return the records in any order and in its own format
+	created := []dnsSdk.RecordResponse{
+		{Id: dnsSdk.PtrString("3"), Name: dnsSdk.PtrString("txt.example.com"), Type: recordTypePtr(dnsSdk.TXT), Content: dnsSdk.PtrString(`"text"`)},
+		{Id: dnsSdk.PtrString("2"), Name: dnsSdk.PtrString("b.example.com"), Type: recordTypePtr(dnsSdk.A), Content: dnsSdk.PtrString("192.0.2.1")},
+		{Id: dnsSdk.PtrString("1"), Name: dnsSdk.PtrString("a.example.com"), Type: recordTypePtr(dnsSdk.A), Content: dnsSdk.PtrString("192.0.2.1")},
+	}
+
+	matches, orphans := matchCreatedRecords(records, created)
+	checkCreatedRecordMatches(t, records, matches, []string{"1", "2", "3", ""})
+	if len(orphans) != 0 {
+		t.Errorf("expected no orphaned records, got %d", len(orphans))
+	}
+
+	// A value normalized in an unknown way is matched if it is the only record left
+	normalized := dnsSdk.RecordResponse{Id: dnsSdk.PtrString("4"), Name: dnsSdk.PtrString("missing.example.com"), Type: recordTypePtr(dnsSdk.A), Content: dnsSdk.PtrString("192.0.2.99")}
+	matches, orphans = matchCreatedRecords(records, append(created, normalized))
+	checkCreatedRecordMatches(t, records, matches, []string{"1", "2", "3", "4"})
+	if len(orphans) != 0 {
+		t.Errorf("expected no orphaned records, got %d", len(orphans))
+	}
+
+	// Otherwise the created records that are left are returned to be deleted
+	extra := dnsSdk.RecordResponse{Id: dnsSdk.PtrString("5"), Name: dnsSdk.PtrString("extra.example.com"), Type: recordTypePtr(dnsSdk.A), Content: dnsSdk.PtrString("192.0.2.1")}
+	matches, orphans = matchCreatedRecords(records, append(created, normalized, extra))
+	checkCreatedRecordMatches(t, records, matches, []string{"1", "2", "3", ""})
+	if len(orphans) != 2 || orphans[0].GetId() != "4" || orphans[1].GetId() != "5" {
+		t.Errorf("expected the orphaned records 4 and 5, got %v", orphans)
+	}
+}
+
+func checkCreatedRecordMatches(t *testing.T, records []dnsSdk.Record, matches []*dnsSdk.RecordResponse, expected []string) {
+	t.Helper()
+	for i := range expected {
+		id := ""
+		if matches[i] != nil {
+			id = matches[i].GetId()
+		}
+		if id != expected[i] {
+			t.Errorf("record %s: expected the created record %q, got %q", records[i].GetName(), expected[i], id)
+		}
+	}
+}
+
+func TestRecordBatcher_Disabled(t *testing.T) {
+	api := newFakeDnsApi()
+	defer api.Close()
+
+	zoneId := api.AddZone("example.com")
+	api.FailRequests("CreateRecords", http.StatusInternalServerError, 1)
+	batcher := newRecordBatcher(newDnsApiClient(api.URL(), defaultAuthHeader, fakeApiKey, "test", nil), 0)
+
+	if _, err := batcher.create(context.Background(), zoneId, testBatchRecord("a.example.com", 3600)); err == nil {
+		t.Errorf("expected the error of the API")
+	}
+	created, err := batcher.create(context.Background(), zoneId, testBatchRecord("a.example.com", 3600))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if created.GetName() != "a.example.com" {
+		t.Errorf("unexpected record %s", created.GetName())
+	}
+}
+
+func testBatchRecord(name string, ttl int32) dnsSdk.Record {
+	record := dnsSdk.NewRecord()
+	record.SetName(name)
+	record.SetType(dnsSdk.A)
+	record.SetContent("192.0.2.1")
+	record.SetTtl(ttl)
+
+	return *record
+}
+
+func recordTypePtr(recordType dnsSdk.RecordTypes) *dnsSdk.RecordTypes {
+	return &recordType
+}
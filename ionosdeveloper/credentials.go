@@ -0,0 +1,138 @@
+package ionosdeveloper
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+const (
+	apiKeyFileEnvVar       = "IONOS_API_KEY_FILE"
+	credentialHelperEnvVar = "IONOS_CREDENTIAL_HELPER"
+)
+
+// credentialArgs are the mutually exclusive ways of providing the credentials
+var credentialArgs = []string{"api_key", "auth", "token", "api_key_file", "credential_helper"}
+
+// credentialHelperRequest is written to the stdin of a credential helper
+type credentialHelperRequest struct {
+	URL     string `json:"url,omitempty"`
+	Profile string `json:"profile,omitempty"`
+}
+
+// credentialHelperResponse is read from the stdout of a credential helper
+type credentialHelperResponse struct {
+	APIKey string `json:"api_key"`
+	Token  string `json:"token"`
+}
+
+// externalCredentials caches the credentials read from files and credential helpers for the lifetime of
+// the provider process, so that helpers are not run again for every configuration of the provider.
+var externalCredentials = struct {
+	mu     sync.Mutex
+	values map[string]credentialHelperResponse
+}{values: map[string]credentialHelperResponse{}}
+
+func conflictingCredentialArgs(key string) []string {
+	var conflicts []string
+	for _, arg := range credentialArgs {
+		if arg != key {
+			conflicts = append(conflicts, arg)
+		}
+	}
+	return conflicts
+}
+
+// resolveExternalCredentials replaces an api_key_file or credential_helper setting by the credentials it
+// provides.
+func resolveExternalCredentials(ctx context.Context, settings *providerSettings, profile string) error {
+	var cacheKey string
+	var load func() (credentialHelperResponse, error)
+
+	switch {
+	case settings.APIKeyFile != "":
+		cacheKey = "file\x00" + settings.APIKeyFile
+		load = func() (credentialHelperResponse, error) {
+			return readApiKeyFile(settings.APIKeyFile)
+		}
+	case settings.CredentialHelper != "":
+		request := credentialHelperRequest{URL: settings.URL, Profile: profile}
+		cacheKey = fmt.Sprintf("helper\x00%s\x00%s\x00%s", settings.CredentialHelper, request.URL, request.Profile)
+		load = func() (credentialHelperResponse, error) {
+			return runCredentialHelper(ctx, settings.CredentialHelper, request)
+		}
+	default:
+		return nil
+	}
+
+	externalCredentials.mu.Lock()
+	defer externalCredentials.mu.Unlock()
+
+	credentials, ok := externalCredentials.values[cacheKey]
+	if !ok {
+		var err error
+		if credentials, err = load(); err != nil {
+			return err
+		}
+		externalCredentials.values[cacheKey] = credentials
+	}
+
+	settings.APIKey, settings.Token = credentials.APIKey, credentials.Token
+	return nil
+}
+
+func readApiKeyFile(path string) (credentialHelperResponse, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return credentialHelperResponse{}, fmt.Errorf("unable to read api_key_file: %v", err)
+	}
+
+	apiKey := strings.TrimSpace(string(content))
+	if apiKey == "" {
+		return credentialHelperResponse{}, fmt.Errorf("api_key_file %s is empty", path)
+	}
+
+	return credentialHelperResponse{APIKey: apiKey}, nil
+}
+
+// runCredentialHelper runs "<helper> get" with a credentialHelperRequest as JSON on stdin and expects a
+// credentialHelperResponse with either api_key or token as JSON on stdout. Like the helpers of git and docker,
+// the helper may contain arguments separated by spaces.
+func runCredentialHelper(ctx context.Context, helper string, request credentialHelperRequest) (credentialHelperResponse, error) {
+	var response credentialHelperResponse
+
+	args := strings.Fields(helper)
+	if len(args) == 0 {
+		return response, fmt.Errorf("credential_helper is empty")
+	}
+
+	input, err := json.Marshal(request)
+	if err != nil {
+		return response, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], append(args[1:], "get")...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return response, fmt.Errorf("credential helper %s failed: %v\n%s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	if err := json.Unmarshal(stdout.Bytes(), &response); err != nil {
+		return response, fmt.Errorf("credential helper %s returned invalid JSON: %v", args[0], err)
+	}
+
+	if (response.APIKey == "") == (response.Token == "") {
+		return response, fmt.Errorf("credential helper %s must return exactly one of api_key and token", args[0])
+	}
+
+	return response, nil
+}
@@ -0,0 +1,162 @@
+package ionosdeveloper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestCredentialHelperProcess is not a real test, it is run as the credential helper by the other tests
+func TestCredentialHelperProcess(t *testing.T) {
+	if os.Getenv("IONOS_TEST_CREDENTIAL_HELPER") == "" {
+		return
+	}
+	defer os.Exit(0)
+
+	args := os.Args
+	if args[len(args)-1] != "get" {
+		fmt.Fprintf(os.Stderr, "unexpected arguments %v", args)
+		os.Exit(2)
+	}
+
+	var request credentialHelperRequest
+	if err := json.NewDecoder(os.Stdin).Decode(&request); err != nil {
+		fmt.Fprint(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	switch request.Profile {
+	case "token":
+		fmt.Print(`{"token": "helper-token"}`)
+	case "failing":
+		fmt.Fprint(os.Stderr, "not logged in")
+		os.Exit(1)
+	default:
+		fmt.Printf(`{"api_key": "helper.%s"}`, strings.TrimPrefix(request.URL, "https://"))
+	}
+}
+
+func testCredentialHelper(t *testing.T) string {
+	t.Setenv("IONOS_TEST_CREDENTIAL_HELPER", "1")
+	return os.Args[0] + " -test.run=TestCredentialHelperProcess --"
+}
+
+func TestResolveExternalCredentials(t *testing.T) {
+	helper := testCredentialHelper(t)
+
+	apiKeyFile := filepath.Join(t.TempDir(), "api_key")
+	if err := os.WriteFile(apiKeyFile, []byte("file.key\n"), 0600); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	cases := []struct {
+		name     string
+		settings providerSettings
+		profile  string
+		expected providerSettings
+	}{
+		{
+			name:     "api key file",
+			settings: providerSettings{APIKeyFile: apiKeyFile},
+			expected: providerSettings{APIKeyFile: apiKeyFile, APIKey: "file.key"},
+		},
+		{
+			name:     "helper api key",
+			settings: providerSettings{CredentialHelper: helper, URL: "https://example.com"},
+			expected: providerSettings{CredentialHelper: helper, URL: "https://example.com", APIKey: "helper.example.com"},
+		},
+		{
+			name:     "helper token",
+			settings: providerSettings{CredentialHelper: helper},
+			profile:  "token",
+			expected: providerSettings{CredentialHelper: helper, Token: "helper-token"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			settings := c.settings
+			if err := resolveExternalCredentials(context.Background(), &settings, c.profile); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			if settings != c.expected {
+				t.Errorf("expected %+v, got %+v", c.expected, settings)
+			}
+		})
+	}
+}
+
+func TestResolveExternalCredentials_Cache(t *testing.T) {
+	apiKeyFile := filepath.Join(t.TempDir(), "api_key")
+	if err := os.WriteFile(apiKeyFile, []byte("first.key"), 0600); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	settings := providerSettings{APIKeyFile: apiKeyFile}
+	if err := resolveExternalCredentials(context.Background(), &settings, ""); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if err := os.WriteFile(apiKeyFile, []byte("second.key"), 0600); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	settings = providerSettings{APIKeyFile: apiKeyFile}
+	if err := resolveExternalCredentials(context.Background(), &settings, ""); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if settings.APIKey != "first.key" {
+		t.Errorf("expected the cached api key, got %s", settings.APIKey)
+	}
+}
+
+func TestResolveExternalCredentials_Errors(t *testing.T) {
+	helper := testCredentialHelper(t)
+	emptyFile := filepath.Join(t.TempDir(), "empty")
+	if err := os.WriteFile(emptyFile, nil, 0600); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	cases := []struct {
+		name     string
+		settings providerSettings
+		profile  string
+		expected string
+	}{
+		{
+			name:     "missing file",
+			settings: providerSettings{APIKeyFile: filepath.Join(t.TempDir(), "missing")},
+			expected: "unable to read api_key_file",
+		},
+		{
+			name:     "empty file",
+			settings: providerSettings{APIKeyFile: emptyFile},
+			expected: "is empty",
+		},
+		{
+			name:     "failing helper",
+			settings: providerSettings{CredentialHelper: helper},
+			profile:  "failing",
+			expected: "not logged in",
+		},
+		{
+			name:     "missing helper",
+			settings: providerSettings{CredentialHelper: filepath.Join(t.TempDir(), "missing-helper")},
+			expected: "credential helper",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			settings := c.settings
+			err := resolveExternalCredentials(context.Background(), &settings, c.profile)
+			if err == nil || !strings.Contains(err.Error(), c.expected) {
+				t.Errorf("expected an error containing %q, got %v", c.expected, err)
+			}
+		})
+	}
+}
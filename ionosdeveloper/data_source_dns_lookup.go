@@ -0,0 +1,213 @@
+package ionosdeveloper
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/miekg/dns"
+
+	dnsSdk "github.com/ionos-developer/dns-sdk-go"
+)
+
+// resolvConfPath configures the nameserver of lookups without a nameserver
+var resolvConfPath = "/etc/resolv.conf"
+
+func dataSourceDnsLookup() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceDnsLookupRead,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.NoZeroValues,
+			},
+			"type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice(recordTypes, true),
+			},
+			"nameserver": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"protocol": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "udp",
+				ValidateFunc: validation.StringInSlice([]string{"udp", "tcp"}, false),
+			},
+			"dnssec": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"rcode": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"authenticated": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"records": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"content": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"prio": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"ttl": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceDnsLookupRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	name := d.Get("name").(string)
+	recordType := strings.ToUpper(d.Get("type").(string))
+
+	nameserver := d.Get("nameserver").(string)
+	if nameserver == "" {
+		config, err := dns.ClientConfigFromFile(resolvConfPath)
+		if err != nil || len(config.Servers) == 0 {
+			return append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Unable to determine the nameserver",
+				Detail:   fmt.Sprintf("No nameserver is configured in %s, set the nameserver argument: %v", resolvConfPath, err),
+			})
+		}
+		nameserver = net.JoinHostPort(config.Servers[0], config.Port)
+	}
+
+	asciiName, err := lookupIdnaProfile.ToASCII(normalizeDomainName(name))
+	if err != nil {
+		asciiName = normalizeDomainName(name)
+	}
+
+	query := new(dns.Msg)
+	query.SetQuestion(dns.Fqdn(asciiName), dns.StringToType[recordType])
+	if d.Get("dnssec").(bool) {
+		query.SetEdns0(4096, true)
+		query.AuthenticatedData = true
+	}
+
+	client := &dns.Client{Net: d.Get("protocol").(string)}
+	response, _, err := client.ExchangeContext(ctx, query, nameserverAddress(nameserver))
+	if err == nil && response.Truncated && client.Net == "udp" {
+		client.Net = "tcp"
+		response, _, err = client.ExchangeContext(ctx, query, nameserverAddress(nameserver))
+	}
+	if err != nil {
+		return append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to query the nameserver",
+			Detail:   fmt.Sprintf("The %s query for %s to %s failed: %v", recordType, name, nameserver, err),
+		})
+	}
+
+	// The answer may contain a CNAME chain and DNSSEC signatures, only the records of the types supported by
+	// ionosdeveloper_dns_record are returned
+	records := []interface{}{}
+	for _, answer := range response.Answer {
+		content, prio, ok := recordDataFromRR(answer)
+		if !ok {
+			continue
+		}
+		records = append(records, map[string]interface{}{
+			"name":    normalizeDomainName(answer.Header().Name),
+			"type":    dns.TypeToString[answer.Header().Rrtype],
+			"content": content,
+			"prio":    int(prio),
+			"ttl":     int(answer.Header().Ttl),
+		})
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s/%s", normalizeDomainName(name), recordType, nameserver))
+	d.Set("rcode", dns.RcodeToString[response.Rcode])
+	d.Set("authenticated", response.AuthenticatedData)
+	d.Set("records", records)
+
+	return diags
+}
+
+// nameserverAddress adds the DNS port to nameservers given without a port
+func nameserverAddress(nameserver string) string {
+	if _, _, err := net.SplitHostPort(nameserver); err != nil {
+		return net.JoinHostPort(nameserver, "53")
+	}
+	return nameserver
+}
+
+// recordDataFromRR returns the content and prio of a resource record in the format of the API, so that it
+// is comparable to the content of ionosdeveloper_dns_record. TXT records are unquoted like the content
+// configured for the record resource. Records of types that the API does not support are skipped.
+func recordDataFromRR(rr dns.RR) (string, int32, bool) {
+	switch rr := rr.(type) {
+	case *dns.A:
+		return rr.A.String(), 0, true
+	case *dns.AAAA:
+		return rr.AAAA.String(), 0, true
+	case *dns.CNAME:
+		return normalizeDomainName(rr.Target), 0, true
+	case *dns.NS:
+		return normalizeDomainName(rr.Ns), 0, true
+	case *dns.MX:
+		return normalizeDomainName(rr.Mx), int32(rr.Preference), true
+	case *dns.TXT:
+		return strings.Join(rr.Txt, ""), 0, true
+	case *dns.SRV:
+		return fmt.Sprintf("%d %d %s", rr.Weight, rr.Port, normalizeDomainName(rr.Target)), int32(rr.Priority), true
+	case *dns.SOA, *dns.CAA:
+		// The content of the other types is the presentation format of the record data
+		return strings.TrimSpace(strings.TrimPrefix(rr.String(), rr.Header().String())), 0, true
+	}
+	return "", 0, false
+}
+
+// recordMatchesRR reports whether a resource record has the content and prio of a record of the API
+func recordMatchesRR(rr dns.RR, record *dnsSdk.RecordResponse) bool {
+	content, prio, ok := recordDataFromRR(rr)
+	if !ok {
+		return false
+	}
+
+	recordType := record.GetType()
+	if (recordType == dnsSdk.MX || recordType == dnsSdk.SRV) && prio != record.GetPrio() {
+		return false
+	}
+	// The content of an SRV record may be its target alone, see the docs of the record resource
+	if srv, ok := rr.(*dns.SRV); ok && recordType == dnsSdk.SRV && normalizeDomainName(srv.Target) == normalizeDomainName(record.GetContent()) {
+		return true
+	}
+	if recordType == dnsSdk.TXT {
+		// The content is unquoted already, unquoting it again would strip the quotes of the text
+		return content == comparableRecordContent(recordType, record.GetContent())
+	}
+	return comparableRecordContent(recordType, content) == comparableRecordContent(recordType, record.GetContent())
+}
@@ -0,0 +1,98 @@
+//go:build all || dns
+
+package ionosdeveloper
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/miekg/dns"
+)
+
+func TestAccDnsLookup(t *testing.T) {
+	var served []dns.RR
+	for _, record := range []string{
+		"www.example.com. 300 IN A 192.0.2.1",
+		"www.example.com. 300 IN A 192.0.2.2",
+		`example.com. 3600 IN TXT "v=spf1" " -all"`,
+		"example.com. 3600 IN MX 10 Mail.Example.com.",
+	} {
+		rr, err := dns.NewRR(record)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		served = append(served, rr)
+	}
+	nameserver := startTestNameserver(t, func() []dns.RR { return served })
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: dnsLookupConfig("www.example.com", "a", nameserver.Addr(), "udp"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ionosdeveloper_dns_lookup.l", "rcode", "NOERROR"),
+					resource.TestCheckResourceAttr("data.ionosdeveloper_dns_lookup.l", "records.#", "2"),
+					resource.TestCheckResourceAttr("data.ionosdeveloper_dns_lookup.l", "records.0.name", "www.example.com"),
+					resource.TestCheckResourceAttr("data.ionosdeveloper_dns_lookup.l", "records.0.type", "A"),
+					resource.TestCheckResourceAttr("data.ionosdeveloper_dns_lookup.l", "records.0.content", "192.0.2.1"),
+					resource.TestCheckResourceAttr("data.ionosdeveloper_dns_lookup.l", "records.0.ttl", "300"),
+				),
+			},
+			{
+				Config: dnsLookupConfig("example.com", "TXT", nameserver.Addr(), "tcp"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ionosdeveloper_dns_lookup.l", "records.#", "1"),
+					resource.TestCheckResourceAttr("data.ionosdeveloper_dns_lookup.l", "records.0.content", "v=spf1 -all"),
+				),
+			},
+			{
+				Config: dnsLookupConfig("example.com", "MX", nameserver.Addr(), "udp"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ionosdeveloper_dns_lookup.l", "records.0.content", "mail.example.com"),
+					resource.TestCheckResourceAttr("data.ionosdeveloper_dns_lookup.l", "records.0.prio", "10"),
+				),
+			},
+			{
+				Config: dnsLookupConfig("missing.example.com", "A", nameserver.Addr(), "udp"),
+				Check:  resource.TestCheckResourceAttr("data.ionosdeveloper_dns_lookup.l", "records.#", "0"),
+			},
+		},
+	})
+}
+
+func TestAccDnsLookup_Record(t *testing.T) {
+	testAccFakeOnly(t)
+	nameserver := startTestNameserver(t, servedFakeRecords)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: txt + fmt.Sprintf(`
+data ionosdeveloper_dns_lookup l {
+  name       = ionosdeveloper_dns_record.r.fqdn
+  type       = "TXT"
+  nameserver = %q
+}`, nameserver.Addr()),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ionosdeveloper_dns_lookup.l", "records.0.content", "text"),
+					resource.TestCheckResourceAttrPair("data.ionosdeveloper_dns_lookup.l", "records.0.content", "ionosdeveloper_dns_record.r", "content"),
+				),
+			},
+		},
+	})
+}
+
+func dnsLookupConfig(name string, recordType string, nameserver string, protocol string) string {
+	return fmt.Sprintf(`
+data ionosdeveloper_dns_lookup l {
+  name       = %q
+  type       = %q
+  nameserver = %q
+  protocol   = %q
+}`, name, recordType, nameserver, protocol)
+}
@@ -23,24 +23,19 @@ func dataSourceDnsZone() *schema.Resource {
 }
 
 func dataSourceDnsZoneRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(SdkBundle).DnsApiClient
 	var diags diag.Diagnostics
 
-	resp, _, err := c.ZonesApi.GetZones(context.Background()).Execute()
+	zone, found, err := m.(SdkBundle).Zones.findByName(ctx, d.Get("name").(string))
 	if err != nil {
 		return appendError(diags, "Unable to get DNS zone", err)
 	}
-
-	zoneName := d.Get("name")
-	for _, zone := range resp {
-		if *zone.Name == zoneName {
-			d.SetId(*zone.Id)
-			return diags
-		}
+	if !found {
+		return append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "DNS zone does not exist",
+		})
 	}
 
-	return append(diags, diag.Diagnostic{
-		Severity: diag.Error,
-		Summary:  "DNS zone does not exist",
-	})
+	d.SetId(zone.GetId())
+	return diags
 }
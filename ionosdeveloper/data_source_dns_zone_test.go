@@ -10,8 +10,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 )
 
-var testZoneName = os.Getenv("TEST_DNS_ZONE_NAME")
-
 func testDnsAccPreCheck(t *testing.T) {
 	testAccPreCheck(t)
 
@@ -22,8 +20,8 @@ func testDnsAccPreCheck(t *testing.T) {
 
 func TestAccZoneOk(t *testing.T) {
 	resource.Test(t, resource.TestCase{
-		PreCheck:          func() { testDnsAccPreCheck(t) },
-		ProviderFactories: testAccProviderFactories,
+		PreCheck:                 func() { testDnsAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: zoneConfig(testZoneName),
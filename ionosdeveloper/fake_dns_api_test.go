@@ -0,0 +1,504 @@
+package ionosdeveloper
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	dnsSdk "github.com/ionos-developer/dns-sdk-go"
+	"golang.org/x/net/idna"
+)
+
+const (
+	fakeApiKey = "fakeprefix.fakesecret"
+	fakeToken  = "faketoken"
+)
+
+var fakeIdnaProfile = idna.New(idna.MapForLookup(), idna.Transitional(true), idna.StrictDomainName(false))
+
+// fakeDnsApi is an in-memory implementation of the zones, records and normalizer endpoints of the IONOS DNS
+// API. It allows running the test suite without credentials, and injecting errors, latency and rate limits
+// that are hard to provoke with the real API.
+type fakeDnsApi struct {
+	server *httptest.Server
+
+	mu       sync.Mutex
+	zones    []*fakeZone
+	lastId   int
+	calls    map[string]int
+	failures []*fakeFailure
+	latency  time.Duration
+
+	rateLimit       int
+	rateWindowStart time.Time
+	rateWindowCount int
+}
+
+type fakeZone struct {
+	id      string
+	name    string
+	records []dnsSdk.RecordResponse
+}
+
+type fakeFailure struct {
+	operation string
+	status    int
+	remaining int
+}
+
+type fakeApiError struct {
+	status  int
+	code    string
+	message string
+}
+
+func newFakeDnsApi() *fakeDnsApi {
+	f := &fakeDnsApi{calls: map[string]int{}}
+	f.server = httptest.NewServer(f)
+	return f
+}
+
+func (f *fakeDnsApi) URL() string {
+	return f.server.URL
+}
+
+func (f *fakeDnsApi) Close() {
+	f.server.Close()
+}
+
+// AddZone creates an empty zone and returns its id
+func (f *fakeDnsApi) AddZone(name string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	zone := &fakeZone{id: f.newId("zone"), name: normalizeDomainName(name)}
+	f.zones = append(f.zones, zone)
+
+	return zone.id
+}
+
+// ZoneId returns the id of the zone with the given name, or an empty string
+func (f *fakeDnsApi) ZoneId(name string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, zone := range f.zones {
+		if zone.name == normalizeDomainName(name) {
+			return zone.id
+		}
+	}
+	return ""
+}
+
+// AddRecord stores a record without validating it and returns its id
+func (f *fakeDnsApi) AddRecord(zoneId string, record dnsSdk.Record) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	zone := f.findZone(zoneId)
+	response := f.newRecordResponse(zone, record)
+	zone.records = append(zone.records, response)
+
+	return response.GetId()
+}
+
+// Records returns a copy of the records of a zone
+func (f *fakeDnsApi) Records(zoneId string) []dnsSdk.RecordResponse {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]dnsSdk.RecordResponse(nil), f.findZone(zoneId).records...)
+}
+
+// Calls returns how often an operation, e.g. GetRecord, has been requested
+func (f *fakeDnsApi) Calls(operation string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.calls[operation]
+}
+
+func (f *fakeDnsApi) ResetCalls() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = map[string]int{}
+}
+
+// FailRequests makes the next count requests of an operation fail with the given status
+func (f *fakeDnsApi) FailRequests(operation string, status int, count int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.failures = append(f.failures, &fakeFailure{operation: operation, status: status, remaining: count})
+}
+
+// SetLatency delays every response
+func (f *fakeDnsApi) SetLatency(latency time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.latency = latency
+}
+
+// SetRateLimit answers with 429 once more than limit requests are made within a second, 0 disables the limit
+func (f *fakeDnsApi) SetRateLimit(limit int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.rateLimit = limit
+	f.rateWindowCount = 0
+}
+
+func (f *fakeDnsApi) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	operation, params := fakeRoute(r.Method, r.URL.Path)
+
+	f.mu.Lock()
+	latency := f.latency
+	f.mu.Unlock()
+	time.Sleep(latency)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls[operation]++
+
+	result, apiErr := f.handle(r, operation, params)
+	if apiErr != nil {
+		writeFakeJson(w, apiErr.status, []dnsSdk.ModelError{{Code: &apiErr.code, Message: &apiErr.message}})
+		return
+	}
+
+	status := http.StatusOK
+	if operation == "CreateRecords" {
+		status = http.StatusCreated
+	}
+	writeFakeJson(w, status, result)
+}
+
+func (f *fakeDnsApi) handle(r *http.Request, operation string, params []string) (interface{}, *fakeApiError) {
+	if r.Header.Get(defaultAuthHeader) != fakeApiKey && r.Header.Get(bearerAuthHeader) != "Bearer "+fakeToken {
+		return nil, &fakeApiError{http.StatusUnauthorized, "UNAUTHORIZED", "The API key is invalid"}
+	}
+
+	if f.rateLimit > 0 {
+		if time.Since(f.rateWindowStart) > time.Second {
+			f.rateWindowStart = time.Now()
+			f.rateWindowCount = 0
+		}
+		f.rateWindowCount++
+		if f.rateWindowCount > f.rateLimit {
+			return nil, &fakeApiError{http.StatusTooManyRequests, "TOO_MANY_REQUESTS", "Rate limit exceeded"}
+		}
+	}
+
+	for i, failure := range f.failures {
+		if failure.operation == operation {
+			if failure.remaining--; failure.remaining <= 0 {
+				f.failures = append(f.failures[:i], f.failures[i+1:]...)
+			}
+			return nil, &fakeApiError{failure.status, "INJECTED_ERROR", "Injected failure for " + operation}
+		}
+	}
+
+	var zone *fakeZone
+	if len(params) > 0 {
+		if zone = f.findZone(params[0]); zone == nil {
+			return nil, &fakeApiError{http.StatusNotFound, "ZONE_NOT_FOUND", "Zone does not exist"}
+		}
+	}
+
+	switch operation {
+	case "GetZones":
+		zones := []dnsSdk.Zone{}
+		for _, zone := range f.zones {
+			zones = append(zones, dnsSdk.Zone{Id: dnsSdk.PtrString(zone.id), Name: dnsSdk.PtrString(zone.name), Type: zoneTypePtr(dnsSdk.NATIVE)})
+		}
+		return zones, nil
+	case "GetZone":
+		query := r.URL.Query()
+		records := []dnsSdk.RecordResponse{}
+		for _, record := range zone.records {
+			if suffix := query.Get("suffix"); suffix != "" && !strings.HasSuffix(record.GetName(), normalizeDomainName(suffix)) {
+				continue
+			}
+			if recordName := query.Get("recordName"); recordName != "" && !strings.HasSuffix(record.GetName(), normalizeDomainName(recordName)) {
+				continue
+			}
+			if recordType := query.Get("recordType"); recordType != "" && string(record.GetType()) != strings.ToUpper(recordType) {
+				continue
+			}
+			records = append(records, record)
+		}
+		return dnsSdk.CustomerZone{Id: dnsSdk.PtrString(zone.id), Name: dnsSdk.PtrString(zone.name), Type: zoneTypePtr(dnsSdk.NATIVE), Records: records}, nil
+	case "PatchZone", "UpdateZone":
+		var records []dnsSdk.Record
+		if err := json.NewDecoder(r.Body).Decode(&records); err != nil {
+			return nil, &fakeApiError{http.StatusBadRequest, "INVALID_REQUEST", err.Error()}
+		}
+		return nil, f.replaceRecords(zone, records, operation == "UpdateZone")
+	case "CreateRecords":
+		var records []dnsSdk.Record
+		if err := json.NewDecoder(r.Body).Decode(&records); err != nil {
+			return nil, &fakeApiError{http.StatusBadRequest, "INVALID_REQUEST", err.Error()}
+		}
+		return f.createRecords(zone, records)
+	case "GetRecord", "UpdateRecord", "DeleteRecord":
+		index := -1
+		for i, record := range zone.records {
+			if record.GetId() == params[1] {
+				index = i
+			}
+		}
+		if index < 0 {
+			return nil, &fakeApiError{http.StatusNotFound, "RECORD_NOT_FOUND", "Record does not exist"}
+		}
+
+		switch operation {
+		case "UpdateRecord":
+			var update dnsSdk.RecordUpdate
+			if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+				return nil, &fakeApiError{http.StatusBadRequest, "INVALID_REQUEST", err.Error()}
+			}
+			return f.updateRecord(zone, index, update)
+		case "DeleteRecord":
+			zone.records = append(zone.records[:index], zone.records[index+1:]...)
+			return nil, nil
+		}
+		return zone.records[index], nil
+	case "NormalizeRecord":
+		var record dnsSdk.Record
+		if err := json.NewDecoder(r.Body).Decode(&record); err != nil {
+			return nil, &fakeApiError{http.StatusBadRequest, "INVALID_REQUEST", err.Error()}
+		}
+		normalized, apiErr := fakeNormalizeRecord(record)
+		if apiErr != nil {
+			return nil, apiErr
+		}
+		return normalized, nil
+	}
+
+	return nil, &fakeApiError{http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("%s %s is not implemented", r.Method, r.URL.Path)}
+}
+
+// createRecords validates the whole batch before storing anything, like the real API
+func (f *fakeDnsApi) createRecords(zone *fakeZone, records []dnsSdk.Record) ([]dnsSdk.RecordResponse, *fakeApiError) {
+	existing := append([]dnsSdk.RecordResponse(nil), zone.records...)
+	created := []dnsSdk.RecordResponse{}
+
+	for _, record := range records {
+		normalized, apiErr := fakeNormalizeRecord(record)
+		if apiErr != nil {
+			return nil, apiErr
+		}
+		if apiErr := validateFakeRecord(zone, existing, normalized, ""); apiErr != nil {
+			return nil, apiErr
+		}
+
+		response := f.newRecordResponse(zone, normalized)
+		existing = append(existing, response)
+		created = append(created, response)
+	}
+
+	zone.records = existing
+
+	return created, nil
+}
+
+func (f *fakeDnsApi) updateRecord(zone *fakeZone, index int, update dnsSdk.RecordUpdate) (interface{}, *fakeApiError) {
+	current := zone.records[index]
+
+	record := dnsSdk.Record{Name: current.Name, Type: current.Type, Content: current.Content, Ttl: current.Ttl, Prio: current.Prio, Disabled: current.Disabled}
+	if update.Content != nil {
+		record.Content = update.Content
+	}
+	if update.Ttl != nil {
+		record.Ttl = update.Ttl
+	}
+	if update.Prio != nil {
+		record.Prio = update.Prio
+	}
+	if update.Disabled != nil {
+		record.Disabled = update.Disabled
+	}
+
+	normalized, apiErr := fakeNormalizeRecord(record)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	if apiErr := validateFakeRecord(zone, zone.records, normalized, current.GetId()); apiErr != nil {
+		return nil, apiErr
+	}
+
+	updated := f.newRecordResponse(zone, normalized)
+	updated.Id = current.Id
+	zone.records[index] = updated
+
+	return updated, nil
+}
+
+// replaceRecords implements PATCH, which replaces the records sharing the name and type of the given records,
+// and PUT, which replaces all records of the zone.
+func (f *fakeDnsApi) replaceRecords(zone *fakeZone, records []dnsSdk.Record, all bool) *fakeApiError {
+	var normalized []dnsSdk.Record
+	replaced := map[string]bool{}
+	for _, record := range records {
+		n, apiErr := fakeNormalizeRecord(record)
+		if apiErr != nil {
+			return apiErr
+		}
+		normalized = append(normalized, n)
+		replaced[n.GetName()+"/"+string(n.GetType())] = true
+	}
+
+	var kept []dnsSdk.RecordResponse
+	for _, record := range zone.records {
+		if !all && !replaced[record.GetName()+"/"+string(record.GetType())] {
+			kept = append(kept, record)
+		}
+	}
+
+	for _, record := range normalized {
+		if apiErr := validateFakeRecord(zone, kept, record, ""); apiErr != nil {
+			return apiErr
+		}
+		kept = append(kept, f.newRecordResponse(zone, record))
+	}
+
+	zone.records = kept
+
+	return nil
+}
+
+func validateFakeRecord(zone *fakeZone, existing []dnsSdk.RecordResponse, record dnsSdk.Record, ignoreId string) *fakeApiError {
+	if record.GetName() != zone.name && !strings.HasSuffix(record.GetName(), "."+zone.name) {
+		return &fakeApiError{http.StatusBadRequest, "INVALID_RECORD", fmt.Sprintf("Record %s is not part of zone %s", record.GetName(), zone.name)}
+	}
+	if record.Ttl != nil && record.GetTtl() < 60 {
+		return &fakeApiError{http.StatusBadRequest, "INVALID_RECORD", "TTL must be at least 60"}
+	}
+
+	for _, other := range existing {
+		if other.GetId() == ignoreId || other.GetName() != record.GetName() {
+			continue
+		}
+		if other.GetType() == record.GetType() && other.GetContent() == record.GetContent() {
+			return &fakeApiError{http.StatusConflict, "DUPLICATE_RECORD", fmt.Sprintf("Record %s %s %s already exists", record.GetName(), record.GetType(), record.GetContent())}
+		}
+		if (other.GetType() == dnsSdk.CNAME) != (record.GetType() == dnsSdk.CNAME) {
+			return &fakeApiError{http.StatusBadRequest, "INVALID_RECORD", fmt.Sprintf("A CNAME record at %s cannot coexist with other records", record.GetName())}
+		}
+	}
+
+	return nil
+}
+
+func fakeNormalizeRecord(record dnsSdk.Record) (dnsSdk.Record, *fakeApiError) {
+	if record.Type == nil || !record.Type.IsValid() {
+		return record, &fakeApiError{http.StatusBadRequest, "INVALID_RECORD", "Record type is invalid"}
+	}
+
+	if record.Name != nil {
+		name, err := fakeIdnaProfile.ToASCII(normalizeDomainName(record.GetName()))
+		if err != nil {
+			return record, &fakeApiError{http.StatusBadRequest, "INVALID_RECORD", err.Error()}
+		}
+		record.SetName(name)
+	}
+
+	content := strings.TrimSpace(record.GetContent())
+	switch record.GetType() {
+	case dnsSdk.TXT:
+		if !strings.HasPrefix(content, `"`) {
+			content = `"` + content + `"`
+		}
+	case dnsSdk.CNAME, dnsSdk.MX, dnsSdk.NS:
+		content = normalizeDomainName(content)
+	}
+	record.SetContent(content)
+
+	return record, nil
+}
+
+func (f *fakeDnsApi) newRecordResponse(zone *fakeZone, record dnsSdk.Record) dnsSdk.RecordResponse {
+	response := dnsSdk.RecordResponse{
+		Id:         dnsSdk.PtrString(f.newId("record")),
+		Name:       dnsSdk.PtrString(record.GetName()),
+		RootName:   dnsSdk.PtrString(zone.name),
+		Type:       record.Type,
+		Content:    dnsSdk.PtrString(record.GetContent()),
+		ChangeDate: dnsSdk.PtrString(time.Now().UTC().Format(time.RFC3339)),
+		Ttl:        dnsSdk.PtrInt32(3600),
+		Prio:       dnsSdk.PtrInt32(record.GetPrio()),
+		Disabled:   dnsSdk.PtrBool(record.GetDisabled()),
+	}
+	if record.Ttl != nil {
+		response.Ttl = dnsSdk.PtrInt32(record.GetTtl())
+	}
+
+	return response
+}
+
+func (f *fakeDnsApi) newId(kind string) string {
+	f.lastId++
+	return fmt.Sprintf("fake-%s-%d", kind, f.lastId)
+}
+
+func (f *fakeDnsApi) findZone(id string) *fakeZone {
+	for _, zone := range f.zones {
+		if zone.id == id {
+			return zone
+		}
+	}
+	return nil
+}
+
+// fakeRoute maps a request to the name of the SDK operation and its path parameters
+func fakeRoute(method string, path string) (string, []string) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+
+	switch {
+	case len(parts) == 2 && parts[0] == "v1" && parts[1] == "zones" && method == http.MethodGet:
+		return "GetZones", nil
+	case len(parts) == 3 && parts[0] == "v1" && parts[1] == "records" && parts[2] == "normalizer" && method == http.MethodPost:
+		return "NormalizeRecord", nil
+	case len(parts) == 3 && parts[0] == "v1" && parts[1] == "zones":
+		switch method {
+		case http.MethodGet:
+			return "GetZone", parts[2:]
+		case http.MethodPatch:
+			return "PatchZone", parts[2:]
+		case http.MethodPut:
+			return "UpdateZone", parts[2:]
+		}
+	case len(parts) == 4 && parts[0] == "v1" && parts[1] == "zones" && parts[3] == "records" && method == http.MethodPost:
+		return "CreateRecords", parts[2:3]
+	case len(parts) == 5 && parts[0] == "v1" && parts[1] == "zones" && parts[3] == "records":
+		switch method {
+		case http.MethodGet:
+			return "GetRecord", []string{parts[2], parts[4]}
+		case http.MethodPut:
+			return "UpdateRecord", []string{parts[2], parts[4]}
+		case http.MethodDelete:
+			return "DeleteRecord", []string{parts[2], parts[4]}
+		}
+	}
+
+	return method + " " + path, nil
+}
+
+func writeFakeJson(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if body != nil {
+		json.NewEncoder(w).Encode(body)
+	}
+}
+
+func zoneTypePtr(zoneType dnsSdk.ZoneTypes) *dnsSdk.ZoneTypes {
+	return &zoneType
+}
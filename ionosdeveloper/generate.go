@@ -0,0 +1,192 @@
+package ionosdeveloper
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	dnsSdk "github.com/ionos-developer/dns-sdk-go"
+)
+
+var invalidResourceNameChars = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+var hclStringReplacer = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	"\n", `\n`,
+	"\r", `\r`,
+	"\t", `\t`,
+	"${", "$${",
+	"%{", "%%{",
+)
+
+// Generate implements the "generate" command of the provider binary. It writes an ionosdeveloper_dns_record
+// block and a matching import block for every record of an existing zone, so that the zone can be brought
+// under Terraform management without recreating its records.
+func Generate(ctx context.Context, args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("generate", flag.ContinueOnError)
+	zoneName := flags.String("zone", "", "Name of the zone to generate the configuration for (required)")
+	output := flags.String("output", "", "File to write the configuration to, defaults to stdout")
+
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return nil
+		}
+		return err
+	}
+
+	if *zoneName == "" {
+		flags.Usage()
+		return fmt.Errorf("the -zone flag is required")
+	}
+
+	client, err := newDnsApiClientFromEnv(ctx, "terraform-provider-ionosdeveloper/generate")
+	if err != nil {
+		return err
+	}
+
+	zone, err := getZoneWithRecords(ctx, client, *zoneName)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	generateZoneConfig(&buf, zone)
+
+	if *output == "" {
+		_, err = stdout.Write(buf.Bytes())
+		return err
+	}
+
+	return os.WriteFile(*output, buf.Bytes(), 0644)
+}
+
+func getZoneWithRecords(ctx context.Context, client *dnsSdk.APIClient, zoneName string) (*dnsSdk.CustomerZone, error) {
+	zones, _, err := client.ZonesApi.GetZones(ctx).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get DNS zones: %v\n%s", err, getIndentedBody(err))
+	}
+
+	for _, zone := range zones {
+		if normalizeDomainName(zone.GetName()) != normalizeDomainName(zoneName) {
+			continue
+		}
+
+		customerZone, _, err := client.ZonesApi.GetZone(ctx, zone.GetId()).Execute()
+		if err != nil {
+			return nil, fmt.Errorf("unable to get DNS zone %s: %v\n%s", zoneName, err, getIndentedBody(err))
+		}
+
+		return customerZone, nil
+	}
+
+	return nil, fmt.Errorf("DNS zone %s does not exist", zoneName)
+}
+
+// generateZoneConfig renders the records of the zone sorted by name, type and content, which keeps both the
+// output and the derived resource names stable between runs.
+func generateZoneConfig(w io.Writer, zone *dnsSdk.CustomerZone) {
+	records := make([]dnsSdk.RecordResponse, len(zone.Records))
+	copy(records, zone.Records)
+
+	sort.SliceStable(records, func(i, j int) bool {
+		if records[i].GetName() != records[j].GetName() {
+			return records[i].GetName() < records[j].GetName()
+		}
+		if records[i].GetType() != records[j].GetType() {
+			return records[i].GetType() < records[j].GetType()
+		}
+		return records[i].GetContent() < records[j].GetContent()
+	})
+
+	fmt.Fprintf(w, "# Generated by terraform-provider-ionosdeveloper from zone %s\n", zone.GetName())
+
+	usedNames := map[string]int{}
+	for _, record := range records {
+		// Ownership markers are written by the provider along with the records they own
+		if isOwnershipMarker(record) {
+			continue
+		}
+
+		resourceName := generateResourceName(zone.GetName(), record.GetName(), string(record.GetType()))
+		usedNames[resourceName]++
+		if count := usedNames[resourceName]; count > 1 {
+			resourceName = fmt.Sprintf("%s_%d", resourceName, count)
+		}
+
+		attributes := [][2]string{
+			{"zone_id", hclString(zone.GetId())},
+			{"name", hclString(record.GetName())},
+			{"type", hclString(string(record.GetType()))},
+			{"content", hclString(record.GetContent())},
+			{"ttl", fmt.Sprint(record.GetTtl())},
+		}
+		// The prio of imported MX and SRV records is read from the API, even if it is 0
+		if record.GetType() == dnsSdk.MX || record.GetType() == dnsSdk.SRV {
+			attributes = append(attributes, [2]string{"prio", fmt.Sprint(record.GetPrio())})
+		}
+		if record.GetDisabled() {
+			attributes = append(attributes, [2]string{"disabled", "true"})
+		}
+
+		fmt.Fprintf(w, "\nresource \"ionosdeveloper_dns_record\" %q {\n", resourceName)
+		writeHclAttributes(w, attributes)
+		fmt.Fprintf(w, "}\n")
+
+		fmt.Fprintf(w, "\nimport {\n")
+		writeHclAttributes(w, [][2]string{
+			{"to", "ionosdeveloper_dns_record." + resourceName},
+			{"id", hclString(zone.GetId() + "/" + record.GetId())},
+		})
+		fmt.Fprintf(w, "}\n")
+	}
+}
+
+// generateResourceName derives a Terraform resource name from the record name relative to the zone and the
+// record type, e.g. www_cname for www.example.com or apex_mx for example.com itself.
+func generateResourceName(zoneName string, recordName string, recordType string) string {
+	name := strings.ToLower(strings.TrimSuffix(recordName, "."))
+	zoneName = strings.ToLower(zoneName)
+
+	if name == zoneName {
+		name = "apex"
+	} else {
+		name = strings.TrimSuffix(name, "."+zoneName)
+	}
+
+	name = strings.ReplaceAll(name, "*", "wildcard")
+	name = invalidResourceNameChars.ReplaceAllString(strings.ReplaceAll(name, ".", "_"), "_")
+	name = strings.Trim(name, "_") + "_" + strings.ToLower(recordType)
+
+	// Resource names must start with a letter
+	if name[0] < 'a' || name[0] > 'z' {
+		name = "r_" + name
+	}
+
+	return name
+}
+
+func writeHclAttributes(w io.Writer, attributes [][2]string) {
+	width := 0
+	for _, attribute := range attributes {
+		if len(attribute[0]) > width {
+			width = len(attribute[0])
+		}
+	}
+
+	for _, attribute := range attributes {
+		fmt.Fprintf(w, "  %-*s = %s\n", width, attribute[0], attribute[1])
+	}
+}
+
+// hclString quotes a value as an HCL string literal, escaping the template sequences that Terraform would
+// otherwise interpolate.
+func hclString(value string) string {
+	return `"` + hclStringReplacer.Replace(value) + `"`
+}
@@ -0,0 +1,143 @@
+package ionosdeveloper
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	dnsSdk "github.com/ionos-developer/dns-sdk-go"
+)
+
+func TestGenerateResourceName(t *testing.T) {
+	cases := []struct {
+		recordName string
+		recordType string
+		expected   string
+	}{
+		{"example.com", "MX", "apex_mx"},
+		{"www.example.com", "CNAME", "www_cname"},
+		{"WWW.Example.com", "a", "www_a"},
+		{"_dmarc.mail.example.com", "TXT", "dmarc_mail_txt"},
+		{"*.example.com", "A", "wildcard_a"},
+		{"1.example.com", "A", "r_1_a"},
+	}
+
+	for _, c := range cases {
+		if actual := generateResourceName("example.com", c.recordName, c.recordType); actual != c.expected {
+			t.Errorf("generateResourceName(%q, %q): expected %q, got %q", c.recordName, c.recordType, c.expected, actual)
+		}
+	}
+}
+
+func TestGenerateZoneConfig(t *testing.T) {
+	zone := dnsSdk.CustomerZone{
+		Id:   dnsSdk.PtrString("zone-1"),
+		Name: dnsSdk.PtrString("example.com"),
+		Records: []dnsSdk.RecordResponse{
+			testRecordResponse("r3", "www.example.com", dnsSdk.A, "2.2.2.2", 3600, 0, false),
+			testRecordResponse("r2", "www.example.com", dnsSdk.A, "1.1.1.1", 3600, 0, true),
+			testRecordResponse("r1", "example.com", dnsSdk.MX, "mx.example.com", 600, 10, false),
+			testRecordResponse("r4", "example.com", dnsSdk.TXT, `"v=spf1 ${x} -all"`, 3600, 0, false),
+			testRecordResponse("r5", "_owner-a.www.example.com", dnsSdk.TXT, ownershipMarkerContent("team-a"), 3600, 0, false),
+		},
+	}
+
+	var buf bytes.Buffer
+	generateZoneConfig(&buf, &zone)
+
+	expected := `# Generated by terraform-provider-ionosdeveloper from zone example.com
+
+resource "ionosdeveloper_dns_record" "apex_mx" {
+  zone_id = "zone-1"
+  name    = "example.com"
+  type    = "MX"
+  content = "mx.example.com"
+  ttl     = 600
+  prio    = 10
+}
+
+import {
+  to = ionosdeveloper_dns_record.apex_mx
+  id = "zone-1/r1"
+}
+
+resource "ionosdeveloper_dns_record" "apex_txt" {
+  zone_id = "zone-1"
+  name    = "example.com"
+  type    = "TXT"
+  content = "\"v=spf1 $${x} -all\""
+  ttl     = 3600
+}
+
+import {
+  to = ionosdeveloper_dns_record.apex_txt
+  id = "zone-1/r4"
+}
+
+resource "ionosdeveloper_dns_record" "www_a" {
+  zone_id  = "zone-1"
+  name     = "www.example.com"
+  type     = "A"
+  content  = "1.1.1.1"
+  ttl      = 3600
+  disabled = true
+}
+
+import {
+  to = ionosdeveloper_dns_record.www_a
+  id = "zone-1/r2"
+}
+
+resource "ionosdeveloper_dns_record" "www_a_2" {
+  zone_id = "zone-1"
+  name    = "www.example.com"
+  type    = "A"
+  content = "2.2.2.2"
+  ttl     = 3600
+}
+
+import {
+  to = ionosdeveloper_dns_record.www_a_2
+  id = "zone-1/r3"
+}
+`
+
+	if buf.String() != expected {
+		t.Errorf("unexpected configuration:\n%s", buf.String())
+	}
+}
+
+func testRecordResponse(id string, name string, recordType dnsSdk.RecordTypes, content string, ttl int32, prio int32, disabled bool) dnsSdk.RecordResponse {
+	return dnsSdk.RecordResponse{
+		Id:       dnsSdk.PtrString(id),
+		Name:     dnsSdk.PtrString(name),
+		Type:     &recordType,
+		Content:  dnsSdk.PtrString(content),
+		Ttl:      dnsSdk.PtrInt32(ttl),
+		Prio:     dnsSdk.PtrInt32(prio),
+		Disabled: dnsSdk.PtrBool(disabled),
+	}
+}
+
+func TestGetZoneWithRecords(t *testing.T) {
+	api := newFakeDnsApi()
+	defer api.Close()
+
+	zoneId := api.AddZone("example.com")
+	client := newDnsApiClient(api.URL(), defaultAuthHeader, fakeApiKey, "test", nil)
+
+	for _, name := range []string{"example.com", "Example.com", "example.com."} {
+		zone, err := getZoneWithRecords(context.Background(), client, name)
+		if err != nil {
+			t.Fatalf("%s: err: %s", name, err)
+		}
+		if zone.GetId() != zoneId {
+			t.Errorf("%s: expected the zone %s, got %s", name, zoneId, zone.GetId())
+		}
+	}
+
+	if _, err := getZoneWithRecords(context.Background(), client, "example.org"); err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Errorf("expected the zone not to exist, got %v", err)
+	}
+}
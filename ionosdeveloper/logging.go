@@ -0,0 +1,142 @@
+package ionosdeveloper
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// httpLogSubsystem can be configured separately with TF_LOG_PROVIDER_IONOSDEVELOPER_DNS_API
+	httpLogSubsystem = "dns_api"
+
+	debugEnvVar = "IONOS_DEBUG"
+
+	maxLoggedBodySize = 64 * 1024
+)
+
+// sensitiveHeaders are masked in addition to the configured auth header
+var sensitiveHeaders = []string{"X-API-Key", "Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// sensitiveJsonFields matches the values of JSON fields like "api_key" or "token" in logged bodies
+var sensitiveJsonFields = regexp.MustCompile(`(?i)("[a-z_-]*(key|token|secret|password)[a-z_-]*"\s*:\s*)"[^"]*"`)
+
+// loggingTransport logs a summary of every request and response to the dns_api subsystem of the provider
+// logger, with the credentials masked, so that the logs can be shared safely.
+type loggingTransport struct {
+	transport http.RoundTripper
+	masked    map[string]bool
+	logBodies bool
+}
+
+func newLoggingTransport(transport http.RoundTripper, authHeader string, logBodies bool) *loggingTransport {
+	masked := map[string]bool{http.CanonicalHeaderKey(authHeader): true}
+	for _, header := range sensitiveHeaders {
+		masked[http.CanonicalHeaderKey(header)] = true
+	}
+
+	return &loggingTransport{transport: transport, masked: masked, logBodies: logBodies}
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := tflog.NewSubsystem(req.Context(), httpLogSubsystem, tflog.WithLevelFromEnv("TF_LOG_PROVIDER_IONOSDEVELOPER", "DNS_API"))
+	ctx = tflog.SubsystemSetField(ctx, httpLogSubsystem, "request_id", newRequestId())
+
+	fields := map[string]interface{}{
+		"method":  req.Method,
+		"url":     req.URL.String(),
+		"headers": t.maskHeaders(req.Header),
+	}
+	if t.logBodies && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			fields["body"] = readLoggedBody(body)
+		}
+	}
+	tflog.SubsystemDebug(ctx, httpLogSubsystem, "Sending HTTP request", fields)
+
+	start := time.Now()
+	resp, err := t.transport.RoundTrip(req)
+	duration := time.Since(start)
+
+	if err != nil {
+		tflog.SubsystemError(ctx, httpLogSubsystem, "HTTP request failed", map[string]interface{}{
+			"duration_ms": duration.Milliseconds(),
+			"error":       err.Error(),
+		})
+		return resp, err
+	}
+
+	fields = map[string]interface{}{
+		"status":      resp.StatusCode,
+		"duration_ms": duration.Milliseconds(),
+		"headers":     t.maskHeaders(resp.Header),
+	}
+	if t.logBodies && resp.Body != nil {
+		body, readErr := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		if readErr != nil {
+			return resp, readErr
+		}
+		fields["body"] = redactBody(body)
+	}
+	tflog.SubsystemDebug(ctx, httpLogSubsystem, "Received HTTP response", fields)
+
+	return resp, nil
+}
+
+func (t *loggingTransport) maskHeaders(headers http.Header) map[string]string {
+	masked := make(map[string]string, len(headers))
+	for key, values := range headers {
+		value := strings.Join(values, ", ")
+		if t.masked[http.CanonicalHeaderKey(key)] {
+			value = maskSecret(value)
+		}
+		masked[key] = value
+	}
+	return masked
+}
+
+// maskSecret keeps the public prefix of API keys and the scheme of authorization headers, which helps to
+// identify the credentials without disclosing them.
+func maskSecret(value string) string {
+	if scheme := strings.Index(value, " "); scheme > 0 {
+		return value[:scheme] + " ***"
+	}
+	if apiKeyFormat.MatchString(value) {
+		return value[:strings.Index(value, ".")] + ".***"
+	}
+	return "***"
+}
+
+func readLoggedBody(body io.ReadCloser) string {
+	defer body.Close()
+
+	content, err := io.ReadAll(io.LimitReader(body, maxLoggedBodySize))
+	if err != nil {
+		return ""
+	}
+	return redactBody(content)
+}
+
+func redactBody(body []byte) string {
+	if len(body) > maxLoggedBodySize {
+		body = body[:maxLoggedBodySize]
+	}
+	return sensitiveJsonFields.ReplaceAllString(string(body), `$1"***"`)
+}
+
+func newRequestId() string {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,66 @@
+package ionosdeveloper
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLoggingTransport_MaskHeaders(t *testing.T) {
+	transport := newLoggingTransport(http.DefaultTransport, "X-Custom-Auth", false)
+
+	masked := transport.maskHeaders(http.Header{
+		"X-Api-Key":     {"publicprefix.secret"},
+		"Authorization": {"Bearer token"},
+		"X-Custom-Auth": {"secret"},
+		"Content-Type":  {"application/json"},
+	})
+
+	expected := map[string]string{
+		"X-Api-Key":     "publicprefix.***",
+		"Authorization": "Bearer ***",
+		"X-Custom-Auth": "***",
+		"Content-Type":  "application/json",
+	}
+	for key, value := range expected {
+		if masked[key] != value {
+			t.Errorf("expected %s to be logged as %q, got %q", key, value, masked[key])
+		}
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	body := `{"name":"test-acc.example.com","api_key":"prefix.secret","accessToken": "token","content":"v=spf1 -all"}`
+	expected := `{"name":"test-acc.example.com","api_key":"***","accessToken": "***","content":"v=spf1 -all"}`
+
+	if redacted := redactBody([]byte(body)); redacted != expected {
+		t.Errorf("expected %s, got %s", expected, redacted)
+	}
+}
+
+func TestLoggingTransport_RoundTrip(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Write(append([]byte("echo: "), body...))
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: newLoggingTransport(http.DefaultTransport, defaultAuthHeader, true)}
+
+	resp, err := client.Post(server.URL, "text/plain", strings.NewReader("request"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer resp.Body.Close()
+
+	// The logged bodies must still be available to the caller
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if string(body) != "echo: request" {
+		t.Errorf("unexpected response body %q", body)
+	}
+}
@@ -0,0 +1,261 @@
+package ionosdeveloper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	dnsSdk "github.com/ionos-developer/dns-sdk-go"
+)
+
+// Ownership markers are TXT records that the provider writes next to the records it creates if owner_id is
+// set, similar to the TXT registry of external-dns. The marker _owner-a.www.example.com owns all A records
+// named www.example.com, since the API has no way to tag single records. Records owned by another owner_id,
+// e.g. records of external-dns, are neither modified nor deleted. Records without a marker are only created
+// or adopted if on_conflict allows it, but records in the state of a resource are claimed by it.
+const (
+	ownershipMarkerPrefix = "_owner-"
+	ownershipHeritage     = "heritage=terraform"
+	ownershipOwnerKey     = "terraform/owner="
+)
+
+var ownerIdFormat = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// recordOwnership serializes the changes of the records of a marker, so that a marker is not deleted while
+// a record it owns is being created
+type recordOwnership struct {
+	ownerId string
+	locks   keyedMutex
+}
+
+// newRecordOwnership returns a registry that is disabled if ownerId is empty
+func newRecordOwnership(ownerId string) *recordOwnership {
+	return &recordOwnership{ownerId: ownerId}
+}
+
+func (o *recordOwnership) enabled() bool {
+	return o != nil && o.ownerId != ""
+}
+
+// lock locks the records of a marker and returns the function unlocking them
+func (o *recordOwnership) lock(zoneId string, markerName string) func() {
+	if !o.enabled() {
+		return func() {}
+	}
+
+	return o.locks.lock(zoneId + "/" + strings.ToLower(markerName))
+}
+
+// ownershipMarkerName returns the name of the marker owning the records with the name and type. The marker of
+// a wildcard name like *.example.com is _owner-a-wildcard.example.com.
+func ownershipMarkerName(name string, recordType dnsSdk.RecordTypes) string {
+	name = comparableRecordName(name)
+	label := ownershipMarkerPrefix + strings.ToLower(string(recordType))
+	if strings.HasPrefix(name, "*.") {
+		return label + "-wildcard." + name[2:]
+	}
+	return label + "." + name
+}
+
+func ownershipMarkerContent(ownerId string) string {
+	return quoteTxtContent(ownershipHeritage + "," + ownershipOwnerKey + ownerId)
+}
+
+// markerOwner returns the owner_id of an ownership marker
+func markerOwner(record dnsSdk.RecordResponse) (string, bool) {
+	if record.GetType() != dnsSdk.TXT || !strings.HasPrefix(strings.ToLower(record.GetName()), ownershipMarkerPrefix) {
+		return "", false
+	}
+
+	fields := strings.Split(unquoteTxtContent(record.GetContent()), ",")
+	if len(fields) != 2 || fields[0] != ownershipHeritage || !strings.HasPrefix(fields[1], ownershipOwnerKey) {
+		return "", false
+	}
+	return strings.TrimPrefix(fields[1], ownershipOwnerKey), true
+}
+
+// isOwnershipMarker reports whether a record is an ownership marker of any owner
+func isOwnershipMarker(record dnsSdk.RecordResponse) bool {
+	_, ok := markerOwner(record)
+	return ok
+}
+
+// ownedRecordSet holds the records with a name and type and the markers owning them
+type ownedRecordSet struct {
+	records []dnsSdk.RecordResponse
+	markers []dnsSdk.RecordResponse
+	owners  []string
+}
+
+func (s ownedRecordSet) ownedBy(ownerId string) bool {
+	for _, owner := range s.owners {
+		if owner == ownerId {
+			return true
+		}
+	}
+	return false
+}
+
+// findOwnedRecordSet reads the records with the name and type from the snapshot of the zone
+func findOwnedRecordSet(ctx context.Context, m interface{}, zoneId string, name string, recordType dnsSdk.RecordTypes) (ownedRecordSet, error) {
+	records, err := m.(SdkBundle).Records.zoneRecords(ctx, zoneId)
+	if err != nil {
+		return ownedRecordSet{}, err
+	}
+
+	set := ownedRecordSet{}
+	name = comparableRecordName(name)
+	markerName := ownershipMarkerName(name, recordType)
+	for _, record := range records {
+		switch {
+		case comparableRecordName(record.GetName()) == name && record.GetType() == recordType:
+			set.records = append(set.records, record)
+		case normalizeDomainName(record.GetName()) == markerName:
+			if owner, ok := markerOwner(record); ok {
+				set.markers = append(set.markers, record)
+				set.owners = append(set.owners, owner)
+			}
+		}
+	}
+	return set, nil
+}
+
+// claimRecordOwnership creates the marker of a record unless the provider owns its name and type already.
+// Existing records without a marker are only claimed if adopt is set. The records of the marker have to be
+// locked by the caller. In read_only mode the ownership is checked, but the marker is not created.
+func claimRecordOwnership(ctx context.Context, m interface{}, zoneId string, record *dnsSdk.Record, adopt bool) error {
+	ownership := m.(SdkBundle).Ownership
+	if !ownership.enabled() {
+		return nil
+	}
+
+	set, err := findOwnedRecordSet(ctx, m, zoneId, record.GetName(), record.GetType())
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case set.ownedBy(ownership.ownerId):
+		return nil
+	case len(set.owners) > 0:
+		return fmt.Errorf("the %s records named %s are owned by %q, not by the owner_id %q of the provider",
+			record.GetType(), record.GetName(), set.owners[0], ownership.ownerId)
+	case len(set.records) > 0 && !adopt:
+		return fmt.Errorf("%d %s records named %s exist without an ownership marker, they are not owned by %q",
+			len(set.records), record.GetType(), record.GetName(), ownership.ownerId)
+	}
+
+	// The request of the record is more useful to report than the one of its marker
+	if m.(SdkBundle).ReadOnly {
+		return nil
+	}
+
+	marker := dnsSdk.NewRecord()
+	marker.SetName(ownershipMarkerName(record.GetName(), record.GetType()))
+	marker.SetType(dnsSdk.TXT)
+	marker.SetContent(ownershipMarkerContent(ownership.ownerId))
+	if record.Ttl != nil {
+		marker.SetTtl(record.GetTtl())
+	}
+
+	tflog.Debug(ctx, "Creating ownership marker", map[string]interface{}{
+		"name":     marker.GetName(),
+		"owner_id": ownership.ownerId,
+	})
+
+	_, err = m.(SdkBundle).Batcher.create(ctx, zoneId, *marker)
+	m.(SdkBundle).Records.invalidate(zoneId)
+	if err != nil {
+		return fmt.Errorf("unable to create the ownership marker %s: %w", marker.GetName(), err)
+	}
+	return nil
+}
+
+// checkRecordOwnership returns an error if the record is owned by another owner_id. Records without a marker
+// are in the state of the caller, e.g. because they were created before owner_id was set, and are claimed
+// by the provider, see claimUnmarkedRecord. It returns the record, or nil if the record does not exist.
+func checkRecordOwnership(ctx context.Context, m interface{}, zoneId string, recordId string) (*dnsSdk.RecordResponse, error) {
+	ownership := m.(SdkBundle).Ownership
+	if !ownership.enabled() {
+		return nil, nil
+	}
+
+	records, err := m.(SdkBundle).Records.zoneRecords(ctx, zoneId)
+	if err != nil {
+		return nil, err
+	}
+
+	var record *dnsSdk.RecordResponse
+	for i := range records {
+		if records[i].GetId() == recordId {
+			record = &records[i]
+			break
+		}
+	}
+	if record == nil {
+		return nil, nil
+	}
+
+	set, err := findOwnedRecordSet(ctx, m, zoneId, record.GetName(), record.GetType())
+	if err != nil {
+		return nil, err
+	}
+	if !set.ownedBy(ownership.ownerId) && len(set.owners) > 0 {
+		return nil, fmt.Errorf("the %s record %s (%s) is owned by %q, not by the owner_id %q of the provider",
+			record.GetType(), record.GetName(), record.GetId(), set.owners[0], ownership.ownerId)
+	}
+
+	return record, nil
+}
+
+// claimUnmarkedRecord creates the marker of a record checked by checkRecordOwnership unless the provider owns
+// it already
+func claimUnmarkedRecord(ctx context.Context, m interface{}, zoneId string, existing *dnsSdk.RecordResponse) error {
+	if existing == nil {
+		return nil
+	}
+
+	record := dnsSdk.NewRecord()
+	record.SetName(existing.GetName())
+	record.SetType(existing.GetType())
+	record.SetTtl(existing.GetTtl())
+
+	unlock := m.(SdkBundle).Ownership.lock(zoneId, ownershipMarkerName(record.GetName(), record.GetType()))
+	defer unlock()
+	return claimRecordOwnership(ctx, m, zoneId, record, true)
+}
+
+// releaseRecordOwnership deletes the markers of the owner_id once no records with the name and type are
+// left. The records of the marker have to be locked by the caller.
+func releaseRecordOwnership(ctx context.Context, m interface{}, zoneId string, name string, recordType dnsSdk.RecordTypes) error {
+	ownership := m.(SdkBundle).Ownership
+	if !ownership.enabled() {
+		return nil
+	}
+
+	set, err := findOwnedRecordSet(ctx, m, zoneId, name, recordType)
+	if err != nil {
+		return err
+	}
+	if len(set.records) > 0 {
+		return nil
+	}
+
+	client := m.(SdkBundle).DnsApiClient
+	for i, marker := range set.markers {
+		if set.owners[i] != ownership.ownerId {
+			continue
+		}
+
+		resp, err := client.RecordsApi.DeleteRecord(ctx, zoneId, marker.GetId()).Execute()
+		m.(SdkBundle).Records.invalidate(zoneId)
+		if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+			return fmt.Errorf("unable to delete the ownership marker %s: %w", marker.GetName(), err)
+		}
+	}
+	return nil
+}
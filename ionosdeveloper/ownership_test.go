@@ -0,0 +1,52 @@
+package ionosdeveloper
+
+import (
+	"testing"
+
+	dnsSdk "github.com/ionos-developer/dns-sdk-go"
+)
+
+func TestOwnershipMarkerName(t *testing.T) {
+	cases := []struct {
+		name       string
+		recordType dnsSdk.RecordTypes
+		expected   string
+	}{
+		{"www.example.com", dnsSdk.A, "_owner-a.www.example.com"},
+		{"Example.com.", dnsSdk.TXT, "_owner-txt.example.com"},
+		{"*.example.com", dnsSdk.CNAME, "_owner-cname-wildcard.example.com"},
+		{"bücher.example.com", dnsSdk.AAAA, "_owner-aaaa.xn--bcher-kva.example.com"},
+		{"straße.example.com", dnsSdk.A, "_owner-a.strasse.example.com"},
+	}
+
+	for _, c := range cases {
+		if name := ownershipMarkerName(c.name, c.recordType); name != c.expected {
+			t.Errorf("ownershipMarkerName(%s, %s): expected %s, got %s", c.name, c.recordType, c.expected, name)
+		}
+	}
+}
+
+func TestMarkerOwner(t *testing.T) {
+	marker := func(name string, recordType dnsSdk.RecordTypes, content string) dnsSdk.RecordResponse {
+		return dnsSdk.RecordResponse{Name: dnsSdk.PtrString(name), Type: &recordType, Content: dnsSdk.PtrString(content)}
+	}
+
+	cases := []struct {
+		record   dnsSdk.RecordResponse
+		expected string
+		ok       bool
+	}{
+		{marker("_owner-a.www.example.com", dnsSdk.TXT, ownershipMarkerContent("team-a")), "team-a", true},
+		{marker("_owner-a.www.example.com", dnsSdk.TXT, "heritage=terraform,terraform/owner=team-b"), "team-b", true},
+		{marker("_owner-a.www.example.com", dnsSdk.TXT, `"heritage=external-dns,external-dns/owner=default"`), "", false},
+		{marker("www.example.com", dnsSdk.TXT, ownershipMarkerContent("team-a")), "", false},
+		{marker("_owner-a.www.example.com", dnsSdk.A, "192.0.2.1"), "", false},
+	}
+
+	for _, c := range cases {
+		owner, ok := markerOwner(c.record)
+		if owner != c.expected || ok != c.ok {
+			t.Errorf("markerOwner(%s %s): expected %q, %t, got %q, %t", c.record.GetName(), c.record.GetContent(), c.expected, c.ok, owner, ok)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package ionosdeveloper
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	dnsSdk "github.com/ionos-developer/dns-sdk-go"
+)
+
+// plannedRecords collects the records planned by the ionosdeveloper_dns_record resources of a Terraform
+// run, so that conflicts between resources are reported at plan time instead of by the API during apply.
+// A provider is configured for every run, which starts with an empty collection.
+type plannedRecords struct {
+	mu      sync.Mutex
+	records map[string][]plannedRecord
+	// released holds the IDs of the existing records that are replaced or deleted by the run, by zone
+	released map[string]map[string]bool
+}
+
+// plannedRecord is a record with its name, type and content in the form returned by the API. id is empty
+// for records that are created.
+type plannedRecord struct {
+	id         string
+	name       string
+	recordType dnsSdk.RecordTypes
+	content    string
+}
+
+type recordConflict int
+
+const (
+	noConflict recordConflict = iota
+	cnameConflict
+	duplicateConflict
+)
+
+func newPlannedRecords() *plannedRecords {
+	return &plannedRecords{records: map[string][]plannedRecord{}, released: map[string]map[string]bool{}}
+}
+
+// add registers a planned record and returns the record planned before that it conflicts with, if any.
+// Each resource is planned once per run, so two records without an id belong to different resources. A
+// record without an id may however be the replacement of a record with an id, e.g. of a tainted resource,
+// which is planned with the same values and must not be reported as a duplicate.
+func (p *plannedRecords) add(zoneId string, record plannedRecord) (plannedRecord, recordConflict) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, other := range p.records[zoneId] {
+		if other.id != "" && other.id == record.id {
+			continue
+		}
+		switch conflictBetween(record, other) {
+		case cnameConflict:
+			return other, cnameConflict
+		case duplicateConflict:
+			if (record.id == "") == (other.id == "") {
+				return other, duplicateConflict
+			}
+		}
+	}
+	p.records[zoneId] = append(p.records[zoneId], record)
+
+	return plannedRecord{}, noConflict
+}
+
+// release registers an existing record that is replaced or deleted by the run, so that it is not reported
+// as a conflict of the records planned after it
+func (p *plannedRecords) release(zoneId string, id string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.released[zoneId] == nil {
+		p.released[zoneId] = map[string]bool{}
+	}
+	p.released[zoneId][id] = true
+}
+
+// isReleased reports whether an existing record has been released by a resource planned so far
+func (p *plannedRecords) isReleased(zoneId string, id string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.released[zoneId][id]
+}
+
+// conflictBetween reports whether two records cannot exist in the same zone
+func conflictBetween(record plannedRecord, other plannedRecord) recordConflict {
+	switch {
+	case record.name != other.name:
+		return noConflict
+	case (record.recordType == dnsSdk.CNAME) != (other.recordType == dnsSdk.CNAME):
+		return cnameConflict
+	case record.recordType == dnsSdk.CNAME && record.content != other.content:
+		return cnameConflict
+	case record.recordType == other.recordType && record.content == other.content:
+		return duplicateConflict
+	}
+	return noConflict
+}
+
+// newPlannedRecord returns a record with a name relative to the zone expanded, in the form returned by the
+// API
+func newPlannedRecord(id string, name string, zoneName string, recordType string, content string) plannedRecord {
+	record := plannedRecord{
+		id:         id,
+		name:       comparableRecordName(expandRecordName(name, zoneName)),
+		recordType: getRecordType(recordType),
+	}
+	record.content = comparableRecordContent(record.recordType, content)
+	return record
+}
+
+// checkPlannedRecord reports a CNAME record at the apex of the zone, a CNAME record next to other records
+// with the same name and duplicate records. The record is compared to the records planned before and to
+// the records of the zone, except for the existing record of the resource and the records released by the
+// resources planned before, see plannedRecords.release. changed is false if the zone, name,
+// type and content of an existing record are not changed, adopting is set for a new record with an
+// on_conflict other than "error". Conflicts that depend on unknown values are left to the API, the caller
+// does not check the record then.
+func checkPlannedRecord(ctx context.Context, m interface{}, zoneId string, zoneName string, record plannedRecord, changed bool, adopting bool) error {
+	if m.(SdkBundle).SkipPlanChecks {
+		return nil
+	}
+
+	if record.recordType == dnsSdk.CNAME && record.name == comparableRecordName(zoneName) {
+		return fmt.Errorf("a CNAME record cannot be created at the apex of the zone %s", zoneName)
+	}
+
+	planned := m.(SdkBundle).PlannedRecords
+	other, conflict := planned.add(zoneId, record)
+
+	// Unchanged records are registered for the checks of the other records only, a conflict is reported
+	// for the record that causes it
+	if !changed {
+		return nil
+	}
+	if conflict != noConflict {
+		return recordConflictError(conflict, other, "is planned by another resource")
+	}
+
+	existing, err := m.(SdkBundle).Records.zoneRecords(ctx, zoneId)
+	if err != nil {
+		return fmt.Errorf("unable to read the records of the zone %s: %v", zoneName, err)
+	}
+
+	for _, r := range existing {
+		// Records of the same type may be adopted by a new resource, see adoptDnsRecord
+		if r.GetId() == record.id || planned.isReleased(zoneId, r.GetId()) || (adopting && r.GetType() == record.recordType) {
+			continue
+		}
+
+		other := plannedRecord{
+			id:         r.GetId(),
+			name:       comparableRecordName(r.GetName()),
+			recordType: r.GetType(),
+			content:    comparableRecordContent(r.GetType(), r.GetContent()),
+		}
+		if conflict := conflictBetween(record, other); conflict == duplicateConflict {
+			return recordConflictError(conflict, other, `exists already, set on_conflict to "adopt" to manage it`)
+		} else if conflict != noConflict {
+			return recordConflictError(conflict, other, "exists already")
+		}
+	}
+
+	return nil
+}
+
+func recordConflictError(conflict recordConflict, other plannedRecord, reason string) error {
+	if conflict == duplicateConflict {
+		return fmt.Errorf("the %s record %s with the content %q %s", other.recordType, other.name, other.content, reason)
+	}
+	return fmt.Errorf("a CNAME record cannot coexist with other records with the same name, the %s record %s with the content %q %s",
+		other.recordType, other.name, other.content, reason)
+}
@@ -0,0 +1,100 @@
+package ionosdeveloper
+
+import (
+	"context"
+	"testing"
+
+	dnsSdk "github.com/ionos-developer/dns-sdk-go"
+)
+
+func TestPlannedRecords_Add(t *testing.T) {
+	a := plannedRecord{name: "www.example.com", recordType: dnsSdk.A, content: "192.0.2.1"}
+	cname := plannedRecord{name: "www.example.com", recordType: dnsSdk.CNAME, content: "example.com"}
+	withId := func(record plannedRecord, id string) plannedRecord {
+		record.id = id
+		return record
+	}
+
+	cases := []struct {
+		name     string
+		records  []plannedRecord
+		expected recordConflict
+	}{
+		{"different names", []plannedRecord{a, {name: "mail.example.com", recordType: dnsSdk.CNAME, content: "example.com"}}, noConflict},
+		{"new duplicates", []plannedRecord{a, a}, duplicateConflict},
+		{"existing duplicates", []plannedRecord{withId(a, "1"), withId(a, "2")}, duplicateConflict},
+		{"replacement", []plannedRecord{withId(a, "1"), a}, noConflict},
+		{"same resource", []plannedRecord{withId(a, "1"), withId(a, "1")}, noConflict},
+		{"CNAME and A", []plannedRecord{withId(a, "1"), cname}, cnameConflict},
+		{"two CNAMEs", []plannedRecord{withId(cname, "1"), {name: cname.name, recordType: dnsSdk.CNAME, content: "example.org"}}, cnameConflict},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			planned := newPlannedRecords()
+			conflict := noConflict
+			for _, record := range c.records {
+				_, conflict = planned.add("zone", record)
+			}
+			if conflict != c.expected {
+				t.Errorf("expected conflict %d, got %d", c.expected, conflict)
+			}
+		})
+	}
+}
+
+func TestCheckPlannedRecord_ZoneRecords(t *testing.T) {
+	api := newFakeDnsApi()
+	defer api.Close()
+
+	zoneId := api.AddZone("example.com")
+	existingId := api.AddRecord(zoneId, dnsSdk.Record{Name: dnsSdk.PtrString("www.example.com"), Type: recordTypePtr(dnsSdk.A), Content: dnsSdk.PtrString("192.0.2.1")})
+	client := newDnsApiClient(api.URL(), defaultAuthHeader, fakeApiKey, "test", nil)
+	cname := newPlannedRecord("", "www", "example.com", "CNAME", "example.org")
+
+	cases := []struct {
+		name     string
+		released string
+		record   plannedRecord
+		valid    bool
+	}{
+		{"conflict", "", cname, false},
+		// The record is planned after the resource of the existing record was planned to be replaced
+		{"released", existingId, cname, true},
+		{"other record released", "other", cname, false},
+		{"same resource", "", newPlannedRecord(existingId, "www", "example.com", "A", "192.0.2.1"), true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := SdkBundle{Records: newRecordCache(client, true), PlannedRecords: newPlannedRecords()}
+			if c.released != "" {
+				m.PlannedRecords.release(zoneId, c.released)
+			}
+
+			err := checkPlannedRecord(context.Background(), m, zoneId, "example.com", c.record, true, false)
+			if (err == nil) != c.valid {
+				t.Errorf("expected valid %t, got %v", c.valid, err)
+			}
+		})
+	}
+}
+
+func TestComparableRecordContent(t *testing.T) {
+	cases := []struct {
+		recordType dnsSdk.RecordTypes
+		content    string
+		expected   string
+	}{
+		{dnsSdk.TXT, `"v=spf1 -all"`, "v=spf1 -all"},
+		{dnsSdk.CNAME, "Example.com.", "example.com"},
+		{dnsSdk.A, " 192.0.2.1 ", "192.0.2.1"},
+		{dnsSdk.AAAA, "2001:DB8:0::1", "2001:db8::1"},
+	}
+
+	for _, c := range cases {
+		if content := comparableRecordContent(c.recordType, c.content); content != c.expected {
+			t.Errorf("comparableRecordContent(%s, %q): expected %q, got %q", c.recordType, c.content, c.expected, content)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package ionosdeveloper
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	profileEnvVar         = "IONOS_PROFILE"
+	configFileEnvVar      = "IONOS_CONFIG_FILE"
+	defaultTtlEnvVar      = "IONOS_DEFAULT_TTL"
+	defaultDisabledEnvVar = "IONOS_DEFAULT_DISABLED"
+
+	defaultProfile = "default"
+)
+
+// profileEnvVars maps the settings that can be stored in a profile to the environment variables they can
+// also be read from.
+var profileEnvVars = map[string]string{
+	"url":               apiUrlEnvVar,
+	"auth_header":       authHeaderEnvVar,
+	"api_key":           apiKeyEnvVar,
+	"token":             tokenEnvVar,
+	"api_key_file":      apiKeyFileEnvVar,
+	"credential_helper": credentialHelperEnvVar,
+	"default_ttl":       defaultTtlEnvVar,
+	"default_disabled":  defaultDisabledEnvVar,
+}
+
+// providerSettings are the connection settings of the provider after the provider arguments, the environment
+// variables and the profiles of the config file have been merged.
+type providerSettings struct {
+	URL        string
+	AuthHeader string
+	APIKey     string
+	Token      string
+
+	APIKeyFile       string
+	CredentialHelper string
+
+	RecordDefaults recordDefaults
+
+	// HTTPClient is configured with the transport arguments of the provider, nil uses the default client
+	HTTPClient *http.Client
+}
+
+// recordDefaults apply to the records that do not configure the attributes themselves
+type recordDefaults struct {
+	// TTL is 0 if the API should choose the TTL
+	TTL      int
+	Disabled bool
+}
+
+// settingsSource describes where the settings come from. Args holds the explicitly configured provider
+// arguments, Profile and ConfigFile may be empty to use the defaults.
+type settingsSource struct {
+	Args       map[string]string
+	Profile    string
+	ConfigFile string
+	Getenv     func(string) string
+}
+
+func defaultConfigFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".ionos", "config")
+}
+
+// resolveProviderSettings merges the settings in the following order of precedence:
+//
+//  1. the explicitly configured provider arguments
+//  2. the profile selected with the profile argument or IONOS_PROFILE
+//  3. the IONOS_* environment variables
+//  4. the default profile of the config file
+//  5. the built-in defaults
+func resolveProviderSettings(source settingsSource) (providerSettings, error) {
+	configFile := source.ConfigFile
+	if configFile == "" {
+		configFile = defaultConfigFile()
+	}
+
+	profiles, err := loadProfiles(configFile)
+	if err != nil {
+		// Only an explicitly configured config file has to exist
+		if !os.IsNotExist(err) || source.ConfigFile != "" || source.Profile != "" {
+			return providerSettings{}, err
+		}
+	}
+
+	layers := []map[string]string{source.Args}
+	if source.Profile != "" {
+		profile, ok := profiles[source.Profile]
+		if !ok {
+			return providerSettings{}, fmt.Errorf("profile %q not found in %s", source.Profile, configFile)
+		}
+		layers = append(layers, profile)
+	}
+
+	env := map[string]string{}
+	for key, envVar := range profileEnvVars {
+		env[key] = source.Getenv(envVar)
+	}
+	layers = append(layers, env, profiles[defaultProfile])
+
+	get := func(key string, defaultValue string) string {
+		for _, layer := range layers {
+			if value := layer[key]; value != "" {
+				return value
+			}
+		}
+		return defaultValue
+	}
+
+	settings := providerSettings{
+		URL:        get("url", ""),
+		AuthHeader: get("auth_header", defaultAuthHeader),
+	}
+
+	if ttl := get("default_ttl", ""); ttl != "" {
+		var err error
+		if settings.RecordDefaults.TTL, err = strconv.Atoi(ttl); err != nil || settings.RecordDefaults.TTL < 60 {
+			return providerSettings{}, fmt.Errorf("default_ttl must be a number of at least 60, got: %s", ttl)
+		}
+	}
+	if disabled := get("default_disabled", ""); disabled != "" {
+		var err error
+		if settings.RecordDefaults.Disabled, err = strconv.ParseBool(disabled); err != nil {
+			return providerSettings{}, fmt.Errorf("default_disabled must be true or false, got: %s", disabled)
+		}
+	}
+
+	// The credentials are taken from a single source, so that e.g. a token of the selected profile is not
+	// combined with an api key of the environment
+	for _, layer := range layers {
+		var provided []string
+		for _, key := range []string{"api_key", "token", "api_key_file", "credential_helper"} {
+			if layer[key] != "" {
+				provided = append(provided, key)
+			}
+		}
+
+		if len(provided) > 1 {
+			return providerSettings{}, fmt.Errorf("only one of api_key, token, api_key_file and credential_helper can be provided, got %s", strings.Join(provided, ", "))
+		}
+		if len(provided) == 1 {
+			settings.APIKey, settings.Token = layer["api_key"], layer["token"]
+			settings.APIKeyFile, settings.CredentialHelper = layer["api_key_file"], layer["credential_helper"]
+			break
+		}
+	}
+
+	return settings, nil
+}
+
+// loadProfiles reads an INI style config file, in which every section is a profile:
+//
+//	[default]
+//	api_key = prefix.secret
+//
+//	[staging]
+//	api_key = prefix.secret
+//	url     = https://api.example.com/dns
+func loadProfiles(path string) (map[string]map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	profiles := map[string]map[string]string{}
+	var profile map[string]string
+	var profileName string
+
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";"):
+			continue
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			profileName = strings.TrimSpace(line[1 : len(line)-1])
+			if _, ok := profiles[profileName]; ok {
+				return nil, fmt.Errorf("%s:%d: duplicate profile %q", path, lineNumber, profileName)
+			}
+			profile = map[string]string{}
+			profiles[profileName] = profile
+			continue
+		}
+
+		separator := strings.Index(line, "=")
+		if separator < 0 {
+			return nil, fmt.Errorf("%s:%d: expected a [profile] or a key = value line", path, lineNumber)
+		}
+		if profile == nil {
+			return nil, fmt.Errorf("%s:%d: setting outside of a [profile] section", path, lineNumber)
+		}
+
+		key := strings.TrimSpace(line[:separator])
+		if _, ok := profileEnvVars[key]; !ok {
+			return nil, fmt.Errorf("%s:%d: unknown setting %q in profile %q", path, lineNumber, key, profileName)
+		}
+		profile[key] = strings.TrimSpace(line[separator+1:])
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return profiles, nil
+}
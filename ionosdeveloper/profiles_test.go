@@ -0,0 +1,174 @@
+package ionosdeveloper
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+const testConfigFile = `
+# Comments and blank lines are ignored
+[default]
+api_key = default.key
+
+[staging]
+api_key     = staging.key
+url         = https://staging.example.com
+auth_header = Authorization
+
+[token]
+token = profile-token
+
+[defaults]
+api_key          = defaults.key
+default_ttl      = 600
+default_disabled = true
+`
+
+func writeTestConfigFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return path
+}
+
+func TestResolveProviderSettings(t *testing.T) {
+	configFile := writeTestConfigFile(t, testConfigFile)
+
+	cases := []struct {
+		name     string
+		args     map[string]string
+		profile  string
+		env      map[string]string
+		expected providerSettings
+	}{
+		{
+			name:     "default profile",
+			expected: providerSettings{APIKey: "default.key", AuthHeader: defaultAuthHeader},
+		},
+		{
+			name:     "env overrides default profile",
+			env:      map[string]string{apiKeyEnvVar: "env.key"},
+			expected: providerSettings{APIKey: "env.key", AuthHeader: defaultAuthHeader},
+		},
+		{
+			name:     "selected profile overrides env",
+			profile:  "staging",
+			env:      map[string]string{apiKeyEnvVar: "env.key", apiUrlEnvVar: "https://env.example.com"},
+			expected: providerSettings{APIKey: "staging.key", URL: "https://staging.example.com", AuthHeader: "Authorization"},
+		},
+		{
+			name:     "credentials are taken from a single source",
+			profile:  "token",
+			env:      map[string]string{apiKeyEnvVar: "env.key"},
+			expected: providerSettings{Token: "profile-token", AuthHeader: defaultAuthHeader},
+		},
+		{
+			name:     "record defaults",
+			profile:  "defaults",
+			args:     map[string]string{"default_ttl": "900"},
+			expected: providerSettings{APIKey: "defaults.key", AuthHeader: defaultAuthHeader, RecordDefaults: recordDefaults{TTL: 900, Disabled: true}},
+		},
+		{
+			name:     "arguments override selected profile",
+			args:     map[string]string{"api_key": "arg.key"},
+			profile:  "staging",
+			expected: providerSettings{APIKey: "arg.key", URL: "https://staging.example.com", AuthHeader: "Authorization"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			settings, err := resolveProviderSettings(settingsSource{
+				Args:       c.args,
+				Profile:    c.profile,
+				ConfigFile: configFile,
+				Getenv:     func(key string) string { return c.env[key] },
+			})
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			if settings != c.expected {
+				t.Errorf("expected %+v, got %+v", c.expected, settings)
+			}
+		})
+	}
+}
+
+func TestResolveProviderSettings_Errors(t *testing.T) {
+	missingFile := filepath.Join(t.TempDir(), "missing")
+	getenv := func(string) string { return "" }
+
+	cases := []struct {
+		name     string
+		source   settingsSource
+		expected string
+	}{
+		{
+			name:     "unknown profile",
+			source:   settingsSource{Profile: "production", ConfigFile: writeTestConfigFile(t, testConfigFile), Getenv: getenv},
+			expected: `profile "production" not found`,
+		},
+		{
+			name:     "missing config file",
+			source:   settingsSource{ConfigFile: missingFile, Getenv: getenv},
+			expected: "no such file",
+		},
+		{
+			name:     "unknown setting",
+			source:   settingsSource{ConfigFile: writeTestConfigFile(t, "[default]\napi_kye = key\n"), Getenv: getenv},
+			expected: `config:2: unknown setting "api_kye"`,
+		},
+		{
+			name:     "api key and token",
+			source:   settingsSource{ConfigFile: writeTestConfigFile(t, "[default]\napi_key = a.b\ntoken = token\n"), Getenv: getenv},
+			expected: "only one of api_key, token, api_key_file and credential_helper can be provided, got api_key, token",
+		},
+		{
+			name:     "invalid default ttl",
+			source:   settingsSource{ConfigFile: writeTestConfigFile(t, "[default]\ndefault_ttl = 30\n"), Getenv: getenv},
+			expected: "default_ttl must be a number of at least 60",
+		},
+		{
+			name:     "setting outside of a profile",
+			source:   settingsSource{ConfigFile: writeTestConfigFile(t, "api_key = key\n"), Getenv: getenv},
+			expected: "setting outside of a [profile] section",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := resolveProviderSettings(c.source)
+			if err == nil || !strings.Contains(err.Error(), c.expected) {
+				t.Errorf("expected an error containing %q, got %v", c.expected, err)
+			}
+		})
+	}
+}
+
+func TestProviderConfigure_Profile(t *testing.T) {
+	configFile := writeTestConfigFile(t, testConfigFile)
+
+	provider := Provider()
+	diags := provider.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		"profile":                     "staging",
+		"config_file":                 configFile,
+		"skip_credentials_validation": true,
+	}))
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	configuration := provider.Meta().(SdkBundle).DnsApiClient.GetConfig()
+	if url := configuration.Servers[0].URL; url != "https://staging.example.com" {
+		t.Errorf("expected the url of the profile, got %s", url)
+	}
+	if apiKey := configuration.DefaultHeader["Authorization"]; apiKey != "staging.key" {
+		t.Errorf("expected the api key of the profile in the Authorization header, got %q", apiKey)
+	}
+}
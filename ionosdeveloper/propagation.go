@@ -0,0 +1,196 @@
+package ionosdeveloper
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/miekg/dns"
+
+	dnsSdk "github.com/ionos-developer/dns-sdk-go"
+)
+
+const (
+	defaultPropagationTimeout      = 5 * time.Minute
+	defaultPropagationPollInterval = 5 * time.Second
+)
+
+// propagationSettings holds the parsed wait_for_propagation attribute of ionosdeveloper_dns_record
+type propagationSettings struct {
+	Nameservers  []string
+	Timeout      time.Duration
+	PollInterval time.Duration
+}
+
+// propagationModel holds the wait_for_propagation attribute of ionosdeveloper_dns_record
+type propagationModel struct {
+	Nameservers  types.List   `tfsdk:"nameservers"`
+	Timeout      types.String `tfsdk:"timeout"`
+	PollInterval types.String `tfsdk:"poll_interval"`
+}
+
+func propagationSchema() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Optional: true,
+		Attributes: map[string]schema.Attribute{
+			// Defaults to the NS records of the zone
+			"nameservers": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"timeout": schema.StringAttribute{
+				Optional:   true,
+				Computed:   true,
+				Default:    stringdefault.StaticString(defaultPropagationTimeout.String()),
+				Validators: []validator.String{positiveDurationValidator{}},
+			},
+			"poll_interval": schema.StringAttribute{
+				Optional:   true,
+				Computed:   true,
+				Default:    stringdefault.StaticString(defaultPropagationPollInterval.String()),
+				Validators: []validator.String{positiveDurationValidator{}},
+			},
+		},
+	}
+}
+
+// propagationSettingsFromObject returns false if the wait_for_propagation attribute is not set
+func propagationSettingsFromObject(ctx context.Context, value types.Object) (propagationSettings, bool, diag.Diagnostics) {
+	if value.IsNull() || value.IsUnknown() {
+		return propagationSettings{}, false, nil
+	}
+
+	var model propagationModel
+	diags := value.As(ctx, &model, basetypes.ObjectAsOptions{})
+	if diags.HasError() {
+		return propagationSettings{}, false, diags
+	}
+
+	// The durations have been checked by positiveDurationValidator
+	settings := propagationSettings{}
+	settings.Timeout, _ = time.ParseDuration(model.Timeout.ValueString())
+	settings.PollInterval, _ = time.ParseDuration(model.PollInterval.ValueString())
+	diags.Append(model.Nameservers.ElementsAs(ctx, &settings.Nameservers, false)...)
+
+	return settings, true, diags
+}
+
+// positiveDurationValidator is validatePositiveDuration for the attributes of terraform-plugin-framework
+type positiveDurationValidator struct{}
+
+func (v positiveDurationValidator) Description(ctx context.Context) string {
+	return "value must be a duration greater than zero"
+}
+
+func (v positiveDurationValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v positiveDurationValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	_, errs := validatePositiveDuration(req.ConfigValue.ValueString(), req.Path.String())
+	for _, err := range errs {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid duration", err.Error())
+	}
+}
+
+// waitForRecordPropagation waits until every nameserver answers with the record. Without nameservers in the
+// settings, the nameservers of the zone are asked. Disabled records are not served, so there is nothing to
+// wait for.
+func waitForRecordPropagation(ctx context.Context, settings propagationSettings, zoneName string, record *dnsSdk.RecordResponse) error {
+	if record.GetDisabled() {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, settings.Timeout)
+	defer cancel()
+
+	nameservers := settings.Nameservers
+	if len(nameservers) == 0 {
+		records, err := net.DefaultResolver.LookupNS(ctx, zoneName)
+		if err != nil {
+			return fmt.Errorf("unable to look up the nameservers of the zone %s: %v", zoneName, err)
+		}
+		for _, ns := range records {
+			nameservers = append(nameservers, ns.Host)
+		}
+	}
+
+	return waitForPropagation(ctx, nameservers, settings.PollInterval, record)
+}
+
+// waitForPropagation polls the nameservers until all of them answer with the record or ctx is done.
+// Nameservers are given as host or host:port.
+func waitForPropagation(ctx context.Context, nameservers []string, pollInterval time.Duration, record *dnsSdk.RecordResponse) error {
+	pending := nameservers
+	for {
+		var lastErr error
+		var stillPending []string
+		for _, nameserver := range pending {
+			served, err := isServedBy(ctx, nameserver, record)
+			if !served {
+				if err != nil {
+					lastErr = err
+				}
+				stillPending = append(stillPending, nameserver)
+			}
+		}
+
+		if len(stillPending) == 0 {
+			return nil
+		}
+		pending = stillPending
+
+		tflog.Debug(ctx, "Waiting for the record to be served", map[string]interface{}{
+			"name":        record.GetName(),
+			"type":        string(record.GetType()),
+			"nameservers": strings.Join(pending, ", "),
+		})
+
+		select {
+		case <-ctx.Done():
+			message := fmt.Sprintf("the %s record %s is not served by %s yet", record.GetType(), record.GetName(), strings.Join(pending, ", "))
+			if lastErr != nil {
+				message += fmt.Sprintf(", last error: %v", lastErr)
+			}
+			return fmt.Errorf("%s", message)
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// isServedBy asks a nameserver for the record without recursion, so that only authoritative data counts
+func isServedBy(ctx context.Context, nameserver string, record *dnsSdk.RecordResponse) (bool, error) {
+	recordType, ok := dns.StringToType[string(record.GetType())]
+	if !ok {
+		return false, fmt.Errorf("unsupported record type %s", record.GetType())
+	}
+
+	query := new(dns.Msg)
+	query.SetQuestion(dns.Fqdn(record.GetName()), recordType)
+	query.RecursionDesired = false
+
+	response, _, err := new(dns.Client).ExchangeContext(ctx, query, nameserverAddress(nameserver))
+	if err != nil {
+		return false, err
+	}
+
+	for _, answer := range response.Answer {
+		if answer.Header().Rrtype == recordType && recordMatchesRR(answer, record) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
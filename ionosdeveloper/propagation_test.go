@@ -0,0 +1,185 @@
+package ionosdeveloper
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+
+	dnsSdk "github.com/ionos-developer/dns-sdk-go"
+)
+
+// testNameserver is an in-process authoritative nameserver answering with the records returned by records,
+// over UDP and TCP on the same port
+type testNameserver struct {
+	server    *dns.Server
+	tcpServer *dns.Server
+	records   func() []dns.RR
+
+	mu      sync.Mutex
+	queries int
+}
+
+func startTestNameserver(t *testing.T, records func() []dns.RR) *testNameserver {
+	conn, listener, err := listenTestNameserver()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	nameserver := &testNameserver{records: records}
+	nameserver.server = startTestDnsServer(&dns.Server{PacketConn: conn, Handler: nameserver})
+	nameserver.tcpServer = startTestDnsServer(&dns.Server{Listener: listener, Handler: nameserver})
+	t.Cleanup(func() {
+		nameserver.server.Shutdown()
+		nameserver.tcpServer.Shutdown()
+	})
+
+	return nameserver
+}
+
+// listenTestNameserver retries when the TCP port matching the free UDP port is taken
+func listenTestNameserver() (net.PacketConn, net.Listener, error) {
+	var err error
+	for attempt := 0; attempt < 10; attempt++ {
+		var conn net.PacketConn
+		if conn, err = net.ListenPacket("udp", "127.0.0.1:0"); err != nil {
+			return nil, nil, err
+		}
+
+		var listener net.Listener
+		if listener, err = net.Listen("tcp", conn.LocalAddr().String()); err == nil {
+			return conn, listener, nil
+		}
+		conn.Close()
+	}
+
+	return nil, nil, err
+}
+
+func startTestDnsServer(server *dns.Server) *dns.Server {
+	started := make(chan struct{})
+	server.NotifyStartedFunc = func() { close(started) }
+	go server.ActivateAndServe()
+	<-started
+
+	return server
+}
+
+func (n *testNameserver) Addr() string {
+	return n.server.PacketConn.LocalAddr().String()
+}
+
+func (n *testNameserver) Queries() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.queries
+}
+
+func (n *testNameserver) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
+	n.mu.Lock()
+	n.queries++
+	n.mu.Unlock()
+
+	response := new(dns.Msg)
+	response.SetReply(r)
+	response.Authoritative = true
+	for _, rr := range n.records() {
+		question := r.Question[0]
+		if strings.EqualFold(rr.Header().Name, question.Name) && rr.Header().Rrtype == question.Qtype {
+			response.Answer = append(response.Answer, rr)
+		}
+	}
+	w.WriteMsg(response)
+}
+
+func TestWaitForPropagation(t *testing.T) {
+	var mu sync.Mutex
+	var served []dns.RR
+	nameserver := startTestNameserver(t, func() []dns.RR {
+		mu.Lock()
+		defer mu.Unlock()
+		return served
+	})
+
+	record := &dnsSdk.RecordResponse{
+		Name:    dnsSdk.PtrString("www.example.com"),
+		Type:    recordTypePtr(dnsSdk.A),
+		Content: dnsSdk.PtrString("192.0.2.1"),
+	}
+
+	// The nameserver serves an outdated record first
+	outdated, _ := dns.NewRR("www.example.com. 3600 IN A 192.0.2.2")
+	current, _ := dns.NewRR("www.example.com. 3600 IN A 192.0.2.1")
+	mu.Lock()
+	served = []dns.RR{outdated}
+	mu.Unlock()
+	time.AfterFunc(50*time.Millisecond, func() {
+		mu.Lock()
+		defer mu.Unlock()
+		served = []dns.RR{current}
+	})
+
+	if err := waitForPropagation(context.Background(), []string{nameserver.Addr()}, 10*time.Millisecond, record); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if queries := nameserver.Queries(); queries < 2 {
+		t.Errorf("expected the nameserver to be polled, got %d queries", queries)
+	}
+}
+
+func TestWaitForPropagation_Timeout(t *testing.T) {
+	nameserver := startTestNameserver(t, func() []dns.RR { return nil })
+
+	record := &dnsSdk.RecordResponse{
+		Name:    dnsSdk.PtrString("www.example.com"),
+		Type:    recordTypePtr(dnsSdk.A),
+		Content: dnsSdk.PtrString("192.0.2.1"),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	err := waitForPropagation(ctx, []string{nameserver.Addr()}, 10*time.Millisecond, record)
+	if err == nil || !strings.Contains(err.Error(), "is not served by "+nameserver.Addr()) {
+		t.Errorf("expected a timeout, got %v", err)
+	}
+}
+
+func TestRecordMatchesRR(t *testing.T) {
+	cases := []struct {
+		rr         string
+		recordType dnsSdk.RecordTypes
+		content    string
+		prio       int32
+		expected   bool
+	}{
+		{"x. IN A 192.0.2.1", dnsSdk.A, "192.0.2.1", 0, true},
+		{"x. IN AAAA 2001:db8::1", dnsSdk.AAAA, "2001:db8:0::1", 0, true},
+		{"x. IN CNAME Example.com.", dnsSdk.CNAME, "example.com", 0, true},
+		{"x. IN MX 10 mail.example.com.", dnsSdk.MX, "mail.example.com", 10, true},
+		{"x. IN MX 10 mail.example.com.", dnsSdk.MX, "mail.example.com", 20, false},
+		{`x. IN TXT "v=spf1" " -all"`, dnsSdk.TXT, `"v=spf1 -all"`, 0, true},
+		{`x. IN TXT "old"`, dnsSdk.TXT, `"new"`, 0, false},
+		{"x. IN SRV 10 5 5060 sip.example.com.", dnsSdk.SRV, "5 5060 sip.example.com", 10, true},
+		{"x. IN SRV 10 5 5060 sip.example.com.", dnsSdk.SRV, "5 5060 sip.example.com", 20, false},
+		{"x. IN SRV 10 5 5060 sip.example.com.", dnsSdk.SRV, "sip.example.com", 10, true},
+		{"x. IN SRV 10 5 5060 sip.example.com.", dnsSdk.SRV, "other.example.com", 10, false},
+		{`x. IN CAA 0 issue "letsencrypt.org"`, dnsSdk.CAA, `0 issue "letsencrypt.org"`, 0, true},
+	}
+
+	for _, c := range cases {
+		rr, err := dns.NewRR(c.rr)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		record := &dnsSdk.RecordResponse{Type: recordTypePtr(c.recordType), Content: dnsSdk.PtrString(c.content), Prio: dnsSdk.PtrInt32(c.prio)}
+		if matches := recordMatchesRR(rr, record); matches != c.expected {
+			t.Errorf("recordMatchesRR(%s, %s): expected %t, got %t", c.rr, c.content, c.expected, matches)
+		}
+	}
+}
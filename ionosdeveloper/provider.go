@@ -4,47 +4,215 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"regexp"
 	"runtime"
+	"strconv"
+	"strings"
+	"time"
 
-	"github.com/hashicorp/terraform-plugin-sdk/meta"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/meta"
 
 	dnsSdk "github.com/ionos-developer/dns-sdk-go"
 )
 
-const apiKeyEnvVar = "IONOS_API_KEY"
+const (
+	apiKeyEnvVar     = "IONOS_API_KEY"
+	apiUrlEnvVar     = "IONOS_API_URL"
+	authHeaderEnvVar = "IONOS_AUTH_HEADER"
+	tokenEnvVar      = "IONOS_TOKEN"
+
+	defaultAuthHeader = "X-API-Key"
+	bearerAuthHeader  = "Authorization"
+)
+
+// IONOS API keys consist of a public prefix and a secret separated by a dot
+var apiKeyFormat = regexp.MustCompile(`^[^.\s]+\.[^.\s]+$`)
 
 type SdkBundle struct {
-	DnsApiClient *dnsSdk.APIClient
+	DnsApiClient   *dnsSdk.APIClient
+	RecordDefaults recordDefaults
+	Zones          *zoneCache
+	Batcher        *recordBatcher
+	Records        *recordCache
+	PlannedRecords *plannedRecords
+	Ownership      *recordOwnership
+	ReadOnly       bool
+	SkipPlanChecks bool
 }
 
-// The provider meta is exported in order to be used by DiffSuppressFunc functions
-var sdkBundle SdkBundle
-
 func Provider() *schema.Provider {
 	provider := &schema.Provider{
 		Schema: map[string]*schema.Schema{
+			// The defaults of url, auth_header and api_key are resolved in providerConfigure, because the
+			// selected profile takes precedence over the environment variables
 			"url": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				DefaultFunc: schema.EnvDefaultFunc("IONOS_API_URL", nil),
+				Type:     schema.TypeString,
+				Optional: true,
 			},
 			"auth_header": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"api_key": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				ValidateFunc:  validateApiKey,
+				ConflictsWith: conflictingCredentialArgs("api_key"),
+			},
+			"auth": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				MaxItems:      1,
+				ConflictsWith: conflictingCredentialArgs("auth"),
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"api_key_prefix": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateApiKeyPart,
+						},
+						"api_key_secret": {
+							Type:         schema.TypeString,
+							Required:     true,
+							Sensitive:    true,
+							ValidateFunc: validateApiKeyPart,
+						},
+					},
+				},
+			},
+			"token": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				ConflictsWith: conflictingCredentialArgs("token"),
+			},
+			"api_key_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: conflictingCredentialArgs("api_key_file"),
+			},
+			"credential_helper": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: conflictingCredentialArgs("credential_helper"),
+			},
+			"profile": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				DefaultFunc: schema.EnvDefaultFunc("IONOS_AUTH_HEADER", "X-API-Key"),
+				DefaultFunc: schema.EnvDefaultFunc(profileEnvVar, nil),
 			},
-			"api_key": {
+			"config_file": {
 				Type:        schema.TypeString,
-				Required:    true,
-				Sensitive:   true,
-				DefaultFunc: schema.EnvDefaultFunc(apiKeyEnvVar, nil),
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(configFileEnvVar, nil),
+			},
+			"skip_credentials_validation": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
 			},
+			"skip_plan_checks": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"defaults": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"ttl": {
+							Type:             schema.TypeInt,
+							Optional:         true,
+							ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(60)),
+						},
+						"disabled": {
+							Type:     schema.TypeBool,
+							Optional: true,
+						},
+					},
+				},
+			},
+			"http_proxy": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsURLWithScheme([]string{"http", "https"}),
+			},
+			"ca_cert_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"ca_cert_pem"},
+			},
+			"ca_cert_pem": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"ca_cert_file"},
+			},
+			"insecure_skip_verify": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"client_cert_file": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"client_key_file"},
+			},
+			"client_key_file": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"client_cert_file"},
+			},
+			"request_timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateDuration,
+			},
+			"create_batch_window": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      defaultCreateBatchWindow.String(),
+				ValidateFunc: validateDuration,
+			},
+			"cache_zone_records": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"read_only": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(readOnlyEnvVar, false),
+			},
+			"write_lock": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      writeLockZone,
+				ValidateFunc: validation.StringInSlice([]string{writeLockZone, writeLockGlobal, writeLockNone}, false),
+			},
+			"owner_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringMatch(ownerIdFormat, "must consist of letters, digits, dots, dashes and underscores"),
+			},
+		},
+		ResourcesMap: map[string]*schema.Resource{
+			"ionosdeveloper_dns_spf":        resourceDnsSpf(),
+			"ionosdeveloper_dns_dmarc":      resourceDnsDmarc(),
+			"ionosdeveloper_dns_dkim":       resourceDnsDkim(),
+			"ionosdeveloper_acme_challenge": resourceAcmeChallenge(),
+		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"ionosdeveloper_dns_zone":   dataSourceDnsZone(),
+			"ionosdeveloper_dns_lookup": dataSourceDnsLookup(),
 		},
-		ResourcesMap:   map[string]*schema.Resource{"ionosdeveloper_dns_record": resourceDnsRecord()},
-		DataSourcesMap: map[string]*schema.Resource{"ionosdeveloper_dns_zone": dataSourceDnsZone()},
 	}
 
 	provider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
@@ -58,46 +226,263 @@ func Provider() *schema.Provider {
 
 		log.Printf("[DEBUG] Setting terraformVersion to %s", terraformVersion)
 
-		tmp, diags := providerConfigure(d, terraformVersion)
-		sdkBundle = tmp.(SdkBundle)
-		return tmp, diags
+		return providerConfigure(ctx, d, terraformVersion)
 	}
 
 	return provider
 }
 
-func providerConfigure(d *schema.ResourceData, terraformVersion string) (interface{}, diag.Diagnostics) {
-	authHeader := d.Get("auth_header").(string)
-	url := d.Get("url").(string)
-	apiKey := d.Get("api_key").(string)
+func providerConfigure(ctx context.Context, d *schema.ResourceData, terraformVersion string) (interface{}, diag.Diagnostics) {
 	var diags diag.Diagnostics
 
-	if apiKey == "" {
+	args := map[string]string{}
+	for _, key := range []string{"url", "auth_header", "api_key", "token", "api_key_file", "credential_helper"} {
+		args[key] = d.Get(key).(string)
+	}
+	if ttl, ok := d.GetOk("defaults.0.ttl"); ok {
+		args["default_ttl"] = strconv.Itoa(ttl.(int))
+	}
+	if disabled, ok := d.GetOk("defaults.0.disabled"); ok {
+		args["default_disabled"] = strconv.FormatBool(disabled.(bool))
+	}
+	if auth, ok := d.GetOk("auth.0"); ok {
+		auth := auth.(map[string]interface{})
+		args["api_key"] = auth["api_key_prefix"].(string) + "." + auth["api_key_secret"].(string)
+	}
+
+	settings, err := resolveProviderSettings(settingsSource{
+		Args:       args,
+		Profile:    d.Get("profile").(string),
+		ConfigFile: d.Get("config_file").(string),
+		Getenv:     os.Getenv,
+	})
+	if err != nil {
 		diags = append(diags, diag.Diagnostic{
 			Severity: diag.Error,
-			Summary:  "Unable to create Ionos Developer client",
-			Detail:   "api_key not provided",
+			Summary:  "Unable to load the Ionos Developer profile",
+			Detail:   err.Error(),
 		})
 
-		return nil, diags
+		return SdkBundle{}, diags
 	}
 
-	configuration := dnsSdk.NewConfiguration()
-	if url != "" {
-		configuration.Servers[0].URL = url
+	if err := resolveExternalCredentials(ctx, &settings, d.Get("profile").(string)); err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to resolve the Ionos Developer credentials",
+			Detail:   err.Error(),
+		})
+
+		return SdkBundle{}, diags
+	}
+
+	if err := validateCredentials(settings); err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to create Ionos Developer client",
+			Detail:   err.Error(),
+		})
+
+		return SdkBundle{}, diags
 	}
-	configuration.AddDefaultHeader(authHeader, apiKey)
-	configuration.UserAgent = fmt.Sprintf(
+
+	userAgent := fmt.Sprintf(
 		"terraform-provider/hashicorp-terraform/%s_terraform-plugin-sdk/%s_os/%s_arch/%s",
 		terraformVersion, meta.SDKVersionString(), runtime.GOOS, runtime.GOARCH)
 
-	if os.Getenv("IONOS_DEBUG") != "" {
-		configuration.Debug = true
+	transport, err := transportSettingsFromResourceData(d)
+	if err == nil {
+		settings.HTTPClient, err = newHTTPClient(transport)
+	}
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to configure the HTTP client",
+			Detail:   err.Error(),
+		})
+
+		return SdkBundle{}, diags
+	}
+	if transport.InsecureSkipVerify {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "The certificate of the DNS API is not verified",
+			Detail:   "insecure_skip_verify is only meant for tests against servers with self-signed certificates. Use ca_cert_file or ca_cert_pem to trust a private CA instead.",
+		})
+	}
+
+	if d.Get("read_only").(bool) {
+		settings.HTTPClient.Transport = newReadOnlyTransport(settings.HTTPClient.Transport)
 	}
+	settings.HTTPClient.Transport = newLoggingTransport(settings.HTTPClient.Transport, settings.AuthHeader, os.Getenv(debugEnvVar) != "")
+	settings.HTTPClient.Transport = newWriteTransport(settings.HTTPClient.Transport, d.Get("write_lock").(string))
 
-	dnsApiClient := dnsSdk.NewAPIClient(configuration)
+	client := newDnsApiClientFromSettings(settings, userAgent)
+
+	// The value has been checked by validateDuration
+	batchWindow, _ := time.ParseDuration(d.Get("create_batch_window").(string))
+
+	if !d.Get("skip_credentials_validation").(bool) {
+		if diags = append(diags, checkCredentials(ctx, client, settings)...); diags.HasError() {
+			return SdkBundle{}, diags
+		}
+	}
 
 	return SdkBundle{
-		DnsApiClient: dnsApiClient,
+		DnsApiClient:   client,
+		RecordDefaults: settings.RecordDefaults,
+		Zones:          newZoneCache(client),
+		Batcher:        newRecordBatcher(client, batchWindow),
+		Records:        newRecordCache(client, d.Get("cache_zone_records").(bool)),
+		PlannedRecords: newPlannedRecords(),
+		Ownership:      newRecordOwnership(d.Get("owner_id").(string)),
+		ReadOnly:       d.Get("read_only").(bool),
+		SkipPlanChecks: d.Get("skip_plan_checks").(bool),
 	}, diags
 }
+
+// validateCredentials checks the format of the credentials, so that obviously broken credentials are
+// reported before any request is made.
+func validateCredentials(settings providerSettings) error {
+	switch {
+	case settings.Token != "":
+		if strings.ContainsAny(settings.Token, " \t\r\n") {
+			return fmt.Errorf("token must not contain whitespace")
+		}
+	case settings.APIKey != "":
+		if !apiKeyFormat.MatchString(settings.APIKey) {
+			return fmt.Errorf("api_key must have the format <prefix>.<secret>")
+		}
+	default:
+		return fmt.Errorf("no credentials provided, set api_key, auth, token, api_key_file or credential_helper in the provider configuration, the matching IONOS_* environment variable or a profile of the config file")
+	}
+
+	return nil
+}
+
+// checkCredentials makes a cheap request with the credentials, so that a revoked key or a key of the wrong
+// account fails the configuration of the provider instead of the first resource operation.
+func checkCredentials(ctx context.Context, client *dnsSdk.APIClient, settings providerSettings) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	_, resp, err := client.ZonesApi.GetZones(ctx).Execute()
+	if err == nil {
+		return diags
+	}
+
+	credentials := "api_key"
+	if settings.Token != "" {
+		credentials = "token"
+	}
+
+	if resp != nil && (resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden) {
+		return append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Invalid Ionos Developer credentials",
+			Detail: fmt.Sprintf("The DNS API rejected the %s with status %d. Check that it is active and has access to the DNS API, "+
+				"or set skip_credentials_validation to skip this check.\n%s", credentials, resp.StatusCode, getIndentedBody(err)),
+		})
+	}
+
+	return append(diags, diag.Diagnostic{
+		Severity: diag.Error,
+		Summary:  "Unable to verify the Ionos Developer credentials",
+		Detail:   fmt.Sprintf("%v\n%s", err, getIndentedBody(err)),
+	})
+}
+
+func validateApiKey(i interface{}, k string) ([]string, []error) {
+	value, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+
+	if !apiKeyFormat.MatchString(value) {
+		return nil, []error{fmt.Errorf("expected %s to have the format <prefix>.<secret>", k)}
+	}
+
+	return nil, nil
+}
+
+func validateApiKeyPart(i interface{}, k string) ([]string, []error) {
+	value, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+
+	if value == "" || strings.ContainsAny(value, ". \t\r\n") {
+		return nil, []error{fmt.Errorf("expected %s to be non-empty and not contain dots or whitespace", k)}
+	}
+
+	return nil, nil
+}
+
+// validateDuration accepts durations that are not negative. 0s is meaningful for create_batch_window and
+// request_timeout, see validatePositiveDuration for the arguments that reject it.
+func validateDuration(i interface{}, k string) ([]string, []error) {
+	value, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+
+	if duration, err := time.ParseDuration(value); err != nil || duration < 0 {
+		return nil, []error{fmt.Errorf("expected %s to be a duration like 30s or 2m that is not negative, got: %s", k, value)}
+	}
+
+	return nil, nil
+}
+
+// validatePositiveDuration rejects 0s in addition to the values rejected by validateDuration
+func validatePositiveDuration(i interface{}, k string) ([]string, []error) {
+	if warnings, errs := validateDuration(i, k); len(errs) > 0 {
+		return warnings, errs
+	}
+
+	if duration, _ := time.ParseDuration(i.(string)); duration == 0 {
+		return nil, []error{fmt.Errorf("expected %s to be greater than zero, got: %s", k, i)}
+	}
+
+	return nil, nil
+}
+
+func newDnsApiClientFromSettings(settings providerSettings, userAgent string) *dnsSdk.APIClient {
+	if settings.Token != "" {
+		return newDnsApiClient(settings.URL, bearerAuthHeader, "Bearer "+settings.Token, userAgent, settings.HTTPClient)
+	}
+	return newDnsApiClient(settings.URL, settings.AuthHeader, settings.APIKey, userAgent, settings.HTTPClient)
+}
+
+func newDnsApiClient(url string, authHeader string, authValue string, userAgent string, httpClient *http.Client) *dnsSdk.APIClient {
+	configuration := dnsSdk.NewConfiguration()
+	configuration.HTTPClient = httpClient
+	if url != "" {
+		configuration.Servers[0].URL = url
+	}
+	configuration.AddDefaultHeader(authHeader, authValue)
+	configuration.UserAgent = userAgent
+
+	return dnsSdk.NewAPIClient(configuration)
+}
+
+// newDnsApiClientFromEnv creates a client for use outside of Terraform, e.g. by the generate command, from
+// the environment variables and profiles the provider arguments default to.
+func newDnsApiClientFromEnv(ctx context.Context, userAgent string) (*dnsSdk.APIClient, error) {
+	settings, err := resolveProviderSettings(settingsSource{
+		Profile:    os.Getenv(profileEnvVar),
+		ConfigFile: os.Getenv(configFileEnvVar),
+		Getenv:     os.Getenv,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := resolveExternalCredentials(ctx, &settings, os.Getenv(profileEnvVar)); err != nil {
+		return nil, err
+	}
+
+	if err := validateCredentials(settings); err != nil {
+		return nil, err
+	}
+
+	return newDnsApiClientFromSettings(settings, userAgent), nil
+}
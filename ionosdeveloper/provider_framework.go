@@ -0,0 +1,117 @@
+package ionosdeveloper
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-mux/tf5to6server"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
+	sdkschema "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// ProviderServerFactory serves the resources migrated to terraform-plugin-framework next to the resources of
+// the SDKv2 provider with protocol version 6. The SDKv2 provider is configured first and owns the
+// configuration, the framework provider shares its SdkBundle.
+func ProviderServerFactory(ctx context.Context) (func() tfprotov6.ProviderServer, error) {
+	sdkProvider := Provider()
+
+	upgradedSdkServer, err := tf5to6server.UpgradeServer(ctx, sdkProvider.GRPCProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	muxServer, err := tf6muxserver.NewMuxServer(ctx,
+		func() tfprotov6.ProviderServer { return upgradedSdkServer },
+		providerserver.NewProtocol6(newFrameworkProvider(sdkProvider)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return muxServer.ProviderServer, nil
+}
+
+// frameworkProvider holds the resources that have been migrated to terraform-plugin-framework
+type frameworkProvider struct {
+	sdkProvider *sdkschema.Provider
+}
+
+func newFrameworkProvider(sdkProvider *sdkschema.Provider) provider.Provider {
+	return &frameworkProvider{sdkProvider: sdkProvider}
+}
+
+func (p *frameworkProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "ionosdeveloper"
+}
+
+// Schema mirrors the schema of the SDKv2 provider, since the mux server requires identical provider schemas.
+// The arguments are validated by the SDKv2 provider only.
+func (p *frameworkProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"url":                         schema.StringAttribute{Optional: true},
+			"auth_header":                 schema.StringAttribute{Optional: true},
+			"api_key":                     schema.StringAttribute{Optional: true, Sensitive: true},
+			"token":                       schema.StringAttribute{Optional: true, Sensitive: true},
+			"api_key_file":                schema.StringAttribute{Optional: true},
+			"credential_helper":           schema.StringAttribute{Optional: true},
+			"profile":                     schema.StringAttribute{Optional: true},
+			"config_file":                 schema.StringAttribute{Optional: true},
+			"skip_credentials_validation": schema.BoolAttribute{Optional: true},
+			"skip_plan_checks":            schema.BoolAttribute{Optional: true},
+			"http_proxy":                  schema.StringAttribute{Optional: true},
+			"ca_cert_file":                schema.StringAttribute{Optional: true},
+			"ca_cert_pem":                 schema.StringAttribute{Optional: true},
+			"insecure_skip_verify":        schema.BoolAttribute{Optional: true},
+			"client_cert_file":            schema.StringAttribute{Optional: true},
+			"client_key_file":             schema.StringAttribute{Optional: true},
+			"request_timeout":             schema.StringAttribute{Optional: true},
+			"create_batch_window":         schema.StringAttribute{Optional: true},
+			"cache_zone_records":          schema.BoolAttribute{Optional: true},
+			"read_only":                   schema.BoolAttribute{Optional: true},
+			"write_lock":                  schema.StringAttribute{Optional: true},
+			"owner_id":                    schema.StringAttribute{Optional: true},
+		},
+		Blocks: map[string]schema.Block{
+			"auth": schema.ListNestedBlock{
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"api_key_prefix": schema.StringAttribute{Required: true},
+						"api_key_secret": schema.StringAttribute{Required: true, Sensitive: true},
+					},
+				},
+			},
+			"defaults": schema.ListNestedBlock{
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"ttl":      schema.Int64Attribute{Optional: true},
+						"disabled": schema.BoolAttribute{Optional: true},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Configure shares the SdkBundle of the SDKv2 provider, which the mux server configures before this provider
+func (p *frameworkProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	if bundle, ok := p.sdkProvider.Meta().(SdkBundle); ok {
+		resp.ResourceData = bundle
+		resp.DataSourceData = bundle
+	}
+}
+
+func (p *frameworkProvider) Resources(ctx context.Context) []func() resource.Resource {
+	return []func() resource.Resource{
+		newDnsRecordResource,
+	}
+}
+
+func (p *frameworkProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
+	return nil
+}
@@ -1,26 +1,203 @@
 package ionosdeveloper
 
 import (
+	"context"
+	"log"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
-var testAccProviderFactories = map[string]func() (*schema.Provider, error){
-	"ionosdeveloper": func() (*schema.Provider, error) {
-		return Provider(), nil
+const testFakeZoneName = "test-acc-fake.com"
+
+var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
+	"ionosdeveloper": func() (tfprotov6.ProviderServer, error) {
+		serverFactory, err := ProviderServerFactory(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		return serverFactory(), nil
 	},
 }
 
+var testZoneName = testEnvDefault("TEST_DNS_ZONE_NAME", testFakeZoneName)
+
+// testFakeDnsApi is set when the tests run against the in-memory fake of the DNS API
+var testFakeDnsApi *fakeDnsApi
+
+// TestMain starts a fake of the DNS API if no API key is provided, so that the acceptance tests can run
+// offline. The provider is pointed to the fake through the url argument.
+func TestMain(m *testing.M) {
+	if os.Getenv(apiKeyEnvVar) == "" {
+		testFakeDnsApi = newFakeDnsApi()
+		testFakeDnsApi.AddZone(testZoneName)
+
+		os.Setenv(apiKeyEnvVar, fakeApiKey)
+		os.Setenv(apiUrlEnvVar, testFakeDnsApi.URL())
+		log.Printf("[INFO] Running tests against the fake DNS API at %s", testFakeDnsApi.URL())
+	}
+
+	// Runs the sweepers instead of the tests if the -sweep flag is set
+	resource.TestMain(m)
+}
+
 func TestProvider(t *testing.T) {
 	if err := Provider().InternalValidate(); err != nil {
 		t.Fatalf("err: %s", err)
 	}
 }
 
+// TestProviderServerFactory checks that the mux server accepts the provider schemas of the SDKv2 and the
+// terraform-plugin-framework providers, which have to be identical
+func TestProviderServerFactory(t *testing.T) {
+	serverFactory, err := ProviderServerFactory(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := serverFactory().GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range resp.Diagnostics {
+		t.Errorf("%s: %s", d.Summary, d.Detail)
+	}
+	if _, ok := resp.ResourceSchemas["ionosdeveloper_dns_record"]; !ok {
+		t.Errorf("ionosdeveloper_dns_record is not served")
+	}
+	if _, ok := resp.ResourceSchemas["ionosdeveloper_dns_spf"]; !ok {
+		t.Errorf("ionosdeveloper_dns_spf is not served")
+	}
+}
+
 func testAccPreCheck(t *testing.T) {
 	if os.Getenv(apiKeyEnvVar) == "" {
 		t.Fatalf("%s must be set for acceptance tests", apiKeyEnvVar)
 	}
 }
+
+// testAccFakeOnly skips tests that rely on injecting failures into the fake DNS API
+func testAccFakeOnly(t *testing.T) {
+	if testFakeDnsApi == nil {
+		t.Skip("requires the fake DNS API")
+	}
+}
+
+func testEnvDefault(key string, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func TestProviderConfigure_Credentials(t *testing.T) {
+	api := newFakeDnsApi()
+	defer api.Close()
+
+	apiKeyFile := filepath.Join(t.TempDir(), "api_key")
+	if err := os.WriteFile(apiKeyFile, []byte(fakeApiKey+"\n"), 0600); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	cases := []struct {
+		name     string
+		config   map[string]interface{}
+		expected string
+		requests int
+	}{
+		{
+			name:     "api key",
+			config:   map[string]interface{}{"api_key": fakeApiKey},
+			requests: 1,
+		},
+		{
+			name: "prefix and secret",
+			config: map[string]interface{}{"auth": []interface{}{map[string]interface{}{
+				"api_key_prefix": "fakeprefix",
+				"api_key_secret": "fakesecret",
+			}}},
+			requests: 1,
+		},
+		{
+			name:     "token",
+			config:   map[string]interface{}{"token": fakeToken},
+			requests: 1,
+		},
+		{
+			name:     "api key file",
+			config:   map[string]interface{}{"api_key_file": apiKeyFile},
+			requests: 1,
+		},
+		{
+			name:     "rejected api key",
+			config:   map[string]interface{}{"api_key": "fakeprefix.revoked"},
+			expected: "Invalid Ionos Developer credentials",
+			requests: 1,
+		},
+		{
+			name:     "malformed api key",
+			config:   map[string]interface{}{"api_key": "fakesecret"},
+			expected: "<prefix>.<secret>",
+		},
+		{
+			name:     "insecure skip verify",
+			config:   map[string]interface{}{"api_key": fakeApiKey, "insecure_skip_verify": true},
+			expected: "The certificate of the DNS API is not verified",
+			requests: 1,
+		},
+		{
+			name:     "skip credentials validation",
+			config:   map[string]interface{}{"api_key": "fakeprefix.revoked", "skip_credentials_validation": true},
+			requests: 0,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			api.ResetCalls()
+			c.config["url"] = api.URL()
+			diags := Provider().Configure(context.Background(), terraform.NewResourceConfigRaw(c.config))
+
+			var errors []string
+			for _, d := range diags {
+				errors = append(errors, d.Summary+": "+d.Detail)
+			}
+			if c.expected == "" && diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %s", strings.Join(errors, "\n"))
+			}
+			if c.expected != "" && !strings.Contains(strings.Join(errors, "\n"), c.expected) {
+				t.Fatalf("expected a diagnostic containing %q, got: %s", c.expected, strings.Join(errors, "\n"))
+			}
+			if requests := api.Calls("GetZones"); requests != c.requests {
+				t.Errorf("expected %d requests, got %d", c.requests, requests)
+			}
+		})
+	}
+}
+
+func TestValidateDuration(t *testing.T) {
+	cases := []struct {
+		value    string
+		valid    bool
+		positive bool
+	}{
+		{"30s", true, true},
+		{"0s", true, false},
+		{"-1s", false, false},
+		{"30", false, false},
+	}
+
+	for _, c := range cases {
+		if _, errs := validateDuration(c.value, "d"); (len(errs) == 0) != c.valid {
+			t.Errorf("validateDuration(%q): expected valid %t, got %v", c.value, c.valid, errs)
+		}
+		if _, errs := validatePositiveDuration(c.value, "d"); (len(errs) == 0) != c.positive {
+			t.Errorf("validatePositiveDuration(%q): expected valid %t, got %v", c.value, c.positive, errs)
+		}
+	}
+}
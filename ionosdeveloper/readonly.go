@@ -0,0 +1,75 @@
+package ionosdeveloper
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	readOnlyEnvVar = "IONOS_READ_ONLY"
+
+	// normalizePath is requested with POST, but only validates a record
+	normalizePath = "/v1/records/normalizer"
+)
+
+// readOnlyTransport fails the requests that would change a zone instead of sending them, see the read_only
+// provider argument. The requests reading zones and records, and normalizing records, are sent as usual.
+type readOnlyTransport struct {
+	transport http.RoundTripper
+}
+
+func newReadOnlyTransport(transport http.RoundTripper) *readOnlyTransport {
+	return &readOnlyTransport{transport: transport}
+}
+
+func (t *readOnlyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if isReadRequest(req) {
+		return t.transport.RoundTrip(req)
+	}
+
+	err := &readOnlyError{Method: req.Method, Path: req.URL.Path}
+	if req.URL.RawQuery != "" {
+		err.Path += "?" + req.URL.RawQuery
+	}
+	if req.Body != nil {
+		body, _ := io.ReadAll(req.Body)
+		req.Body.Close()
+
+		var indented bytes.Buffer
+		if json.Indent(&indented, body, "", "  ") == nil {
+			body = indented.Bytes()
+		}
+		err.Body = string(body)
+	}
+
+	return nil, err
+}
+
+func isReadRequest(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	case http.MethodPost:
+		return strings.HasSuffix(req.URL.Path, normalizePath)
+	}
+	return false
+}
+
+// readOnlyError describes the request that has not been sent because the provider is read-only
+type readOnlyError struct {
+	Method string
+	Path   string
+	Body   string
+}
+
+func (e *readOnlyError) Error() string {
+	message := fmt.Sprintf("The provider is read_only, the following request has not been sent:\n\n%s %s", e.Method, e.Path)
+	if e.Body != "" {
+		message += "\n" + e.Body
+	}
+	return message
+}
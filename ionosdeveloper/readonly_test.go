@@ -0,0 +1,47 @@
+package ionosdeveloper
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestReadOnlyTransport(t *testing.T) {
+	var sent int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&sent, 1)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: newReadOnlyTransport(http.DefaultTransport)}
+
+	for _, req := range []struct{ method, path string }{
+		{http.MethodGet, "/dns/v1/zones"},
+		{http.MethodPost, "/dns/v1/records/normalizer"},
+	} {
+		request, _ := http.NewRequest(req.method, server.URL+req.path, strings.NewReader(`{}`))
+		resp, err := client.Do(request)
+		if err != nil {
+			t.Fatalf("%s %s: err: %s", req.method, req.path, err)
+		}
+		resp.Body.Close()
+	}
+
+	request, _ := http.NewRequest(http.MethodPut, server.URL+"/dns/v1/zones/z/records/r", strings.NewReader(`{"content":"192.0.2.1"}`))
+	_, err := client.Do(request)
+
+	var readOnly *readOnlyError
+	if !errors.As(err, &readOnly) {
+		t.Fatalf("expected a readOnlyError, got %v", err)
+	}
+	expected := "PUT /dns/v1/zones/z/records/r\n{\n  \"content\": \"192.0.2.1\"\n}"
+	if !strings.HasSuffix(readOnly.Error(), expected) {
+		t.Errorf("expected the request to be described as %q, got %q", expected, readOnly.Error())
+	}
+	if sent != 2 {
+		t.Errorf("expected 2 requests to be sent, got %d", sent)
+	}
+}
@@ -0,0 +1,111 @@
+package ionosdeveloper
+
+import (
+	"context"
+	"sync"
+
+	dnsSdk "github.com/ionos-developer/dns-sdk-go"
+)
+
+// recordCache serves the records of a zone from a snapshot of the zone, so that refreshing the records of a
+// zone costs a single GetZone request instead of a GetRecord request per record. Terraform refreshes the
+// resources in parallel, concurrent reads of a zone share the request loading the snapshot. A snapshot is
+// kept until a record of the zone is written.
+type recordCache struct {
+	client  *dnsSdk.APIClient
+	enabled bool
+
+	mu        sync.Mutex
+	snapshots map[string]*zoneSnapshot
+}
+
+type zoneSnapshot struct {
+	// loaded is closed once records or err are set
+	loaded  chan struct{}
+	records map[string]dnsSdk.RecordResponse
+	err     error
+}
+
+// newRecordCache returns a cache that reads every record with GetRecord if it is not enabled
+func newRecordCache(client *dnsSdk.APIClient, enabled bool) *recordCache {
+	return &recordCache{client: client, enabled: enabled, snapshots: map[string]*zoneSnapshot{}}
+}
+
+// get returns a record of the snapshot of its zone. Records that are not part of the snapshot, e.g. because
+// they have been deleted, are requested with GetRecord, so that the caller receives the error of the API.
+func (c *recordCache) get(ctx context.Context, zoneId string, recordId string) (*dnsSdk.RecordResponse, error) {
+	if c.enabled {
+		snapshot := c.snapshot(ctx, zoneId)
+		if snapshot.err == nil {
+			if record, ok := snapshot.records[recordId]; ok {
+				return &record, nil
+			}
+		}
+	}
+
+	record, _, err := c.client.RecordsApi.GetRecord(ctx, zoneId, recordId).Execute()
+	return record, err
+}
+
+// zoneRecords returns the records of a zone from its snapshot, even if the cache is not enabled for
+// reading records.
+func (c *recordCache) zoneRecords(ctx context.Context, zoneId string) ([]dnsSdk.RecordResponse, error) {
+	snapshot := c.snapshot(ctx, zoneId)
+	if snapshot.err != nil {
+		return nil, snapshot.err
+	}
+
+	records := make([]dnsSdk.RecordResponse, 0, len(snapshot.records))
+	for _, record := range snapshot.records {
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// snapshot returns the snapshot of a zone, loading it if necessary. A failed load is not kept, the next read
+// tries again.
+func (c *recordCache) snapshot(ctx context.Context, zoneId string) *zoneSnapshot {
+	c.mu.Lock()
+	snapshot, ok := c.snapshots[zoneId]
+	if !ok {
+		snapshot = &zoneSnapshot{loaded: make(chan struct{})}
+		c.snapshots[zoneId] = snapshot
+		// The snapshot is shared, so it is loaded even if the read that started it is cancelled
+		go c.load(context.WithoutCancel(ctx), zoneId, snapshot)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-snapshot.loaded:
+		return snapshot
+	case <-ctx.Done():
+		return &zoneSnapshot{err: ctx.Err()}
+	}
+}
+
+func (c *recordCache) load(ctx context.Context, zoneId string, snapshot *zoneSnapshot) {
+	zone, _, err := c.client.ZonesApi.GetZone(ctx, zoneId).Execute()
+	if err != nil {
+		snapshot.err = err
+		c.mu.Lock()
+		if c.snapshots[zoneId] == snapshot {
+			delete(c.snapshots, zoneId)
+		}
+		c.mu.Unlock()
+	} else {
+		snapshot.records = make(map[string]dnsSdk.RecordResponse, len(zone.GetRecords()))
+		for _, record := range zone.GetRecords() {
+			snapshot.records[record.GetId()] = record
+		}
+	}
+	close(snapshot.loaded)
+}
+
+// invalidate drops the snapshot of a zone after one of its records has been written. Reads that are
+// waiting for a snapshot being loaded at the same time still receive it.
+func (c *recordCache) invalidate(zoneId string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.snapshots, zoneId)
+}
@@ -0,0 +1,149 @@
+package ionosdeveloper
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	dnsSdk "github.com/ionos-developer/dns-sdk-go"
+)
+
+func TestRecordCache(t *testing.T) {
+	api := newFakeDnsApi()
+	defer api.Close()
+
+	zoneId := api.AddZone("example.com")
+	recordIds := addTestRecords(api, zoneId, 20)
+	cache := newRecordCache(newDnsApiClient(api.URL(), defaultAuthHeader, fakeApiKey, "test", nil), true)
+
+	readTestRecords(t, cache, zoneId, recordIds)
+	if calls := api.Calls("GetZone"); calls != 1 {
+		t.Errorf("expected the concurrent reads to share a GetZone request, got %d", calls)
+	}
+	if calls := api.Calls("GetRecord"); calls != 0 {
+		t.Errorf("expected no GetRecord requests, got %d", calls)
+	}
+
+	readTestRecords(t, cache, zoneId, recordIds)
+	if calls := api.Calls("GetZone"); calls != 1 {
+		t.Errorf("expected the snapshot to be kept, got %d GetZone requests", calls)
+	}
+
+	cache.invalidate(zoneId)
+	readTestRecords(t, cache, zoneId, recordIds)
+	if calls := api.Calls("GetZone"); calls != 2 {
+		t.Errorf("expected the snapshot to be reloaded after a write, got %d GetZone requests", calls)
+	}
+
+	// Records missing from the snapshot are reported by GetRecord
+	if _, err := cache.get(context.Background(), zoneId, "missing"); err == nil {
+		t.Errorf("expected an error for a missing record")
+	}
+	if calls := api.Calls("GetRecord"); calls != 1 {
+		t.Errorf("expected a GetRecord request for the missing record, got %d", calls)
+	}
+}
+
+func TestRecordCache_CancelledRead(t *testing.T) {
+	api := newFakeDnsApi()
+	defer api.Close()
+
+	zoneId := api.AddZone("example.com")
+	recordIds := addTestRecords(api, zoneId, 5)
+	cache := newRecordCache(newDnsApiClient(api.URL(), defaultAuthHeader, fakeApiKey, "test", nil), true)
+	api.SetLatency(200 * time.Millisecond)
+
+	// The read starting the load leaves before the snapshot has been loaded, the other reads still receive it
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := cache.zoneRecords(ctx, zoneId); err != context.DeadlineExceeded {
+		t.Fatalf("expected the cancelled read to fail, got %v", err)
+	}
+	api.SetLatency(0)
+
+	readTestRecords(t, cache, zoneId, recordIds)
+	if calls := api.Calls("GetZone"); calls != 1 {
+		t.Errorf("expected the reads to share the GetZone request of the cancelled read, got %d", calls)
+	}
+	if calls := api.Calls("GetRecord"); calls != 0 {
+		t.Errorf("expected no GetRecord requests, got %d", calls)
+	}
+}
+
+func TestRecordCache_Disabled(t *testing.T) {
+	api := newFakeDnsApi()
+	defer api.Close()
+
+	zoneId := api.AddZone("example.com")
+	recordIds := addTestRecords(api, zoneId, 5)
+	cache := newRecordCache(newDnsApiClient(api.URL(), defaultAuthHeader, fakeApiKey, "test", nil), false)
+
+	readTestRecords(t, cache, zoneId, recordIds)
+	if calls := api.Calls("GetZone"); calls != 0 {
+		t.Errorf("expected no GetZone requests, got %d", calls)
+	}
+	if calls := api.Calls("GetRecord"); calls != len(recordIds) {
+		t.Errorf("expected a GetRecord request per record, got %d", calls)
+	}
+}
+
+// BenchmarkRecordCache reports the requests needed to refresh a zone of 300 records, e.g.
+// go test -run xxx -bench RecordCache ./ionosdeveloper
+func BenchmarkRecordCache(b *testing.B) {
+	for _, enabled := range []bool{true, false} {
+		b.Run(fmt.Sprintf("enabled=%t", enabled), func(b *testing.B) {
+			api := newFakeDnsApi()
+			defer api.Close()
+
+			zoneId := api.AddZone("example.com")
+			recordIds := addTestRecords(api, zoneId, 300)
+			client := newDnsApiClient(api.URL(), defaultAuthHeader, fakeApiKey, "test", nil)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				readTestRecords(b, newRecordCache(client, enabled), zoneId, recordIds)
+			}
+
+			b.ReportMetric(float64(api.Calls("GetZone")+api.Calls("GetRecord"))/float64(b.N), "requests/op")
+		})
+	}
+}
+
+func addTestRecords(api *fakeDnsApi, zoneId string, count int) []string {
+	var recordIds []string
+	for i := 0; i < count; i++ {
+		record := dnsSdk.NewRecord()
+		record.SetName(fmt.Sprintf("r%d.example.com", i))
+		record.SetType(dnsSdk.A)
+		record.SetContent("192.0.2.1")
+		recordIds = append(recordIds, api.AddRecord(zoneId, *record))
+	}
+	return recordIds
+}
+
+// readTestRecords reads the records with the parallelism of a Terraform refresh
+func readTestRecords(t testing.TB, cache *recordCache, zoneId string, recordIds []string) {
+	var wg sync.WaitGroup
+	ids := make(chan string)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range ids {
+				record, err := cache.get(context.Background(), zoneId, id)
+				if err != nil {
+					t.Errorf("unexpected error: %s", err)
+				} else if record.GetId() != id {
+					t.Errorf("expected record %s, got %s", id, record.GetId())
+				}
+			}
+		}()
+	}
+	for _, id := range recordIds {
+		ids <- id
+	}
+	close(ids)
+	wg.Wait()
+}
@@ -0,0 +1,160 @@
+package ionosdeveloper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	dnsSdk "github.com/ionos-developer/dns-sdk-go"
+)
+
+const acmeChallengeLabel = "_acme-challenge"
+
+func resourceAcmeChallenge() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceAcmeChallengeCreate,
+		ReadContext:   resourceAcmeChallengeRead,
+		DeleteContext: resourceAcmeChallengeDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"domain": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return acmeChallengeName(old) == acmeChallengeName(new)
+				},
+			},
+			"digest": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^[A-Za-z0-9_-]+$`), "must be a base64url encoded digest"),
+			},
+			"ttl": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				ForceNew:         true,
+				Default:          60,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(60)),
+			},
+			"wait_for_record": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+				Default:  false,
+			},
+			// Defaults to the NS records of the zone, see waitForRecordPropagation
+			"nameservers": {
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"zone_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceAcmeChallengeCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	name := acmeChallengeName(d.Get("domain").(string))
+	digest := d.Get("digest").(string)
+
+	zone, ok, err := m.(SdkBundle).Zones.findForRecordName(ctx, name)
+	if err != nil {
+		return appendError(diags, "Unable to get DNS zones", err)
+	}
+	if !ok {
+		return append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "DNS zone does not exist",
+			Detail:   fmt.Sprintf("No zone found for %s", name),
+		})
+	}
+
+	record := dnsSdk.NewRecord()
+	record.SetName(name)
+	record.SetType(dnsSdk.TXT)
+	record.SetContent(digest)
+	record.SetTtl(int32(d.Get("ttl").(int)))
+
+	d.Set("zone_id", zone.GetId())
+	createdRecord, diags := createDnsRecord(ctx, m, zone.GetId(), record)
+	if diags.HasError() {
+		return diags
+	}
+	d.SetId(createdRecord.GetId())
+	d.Set("name", name)
+
+	// The ACME server validates the challenge with the authoritative nameservers, not with the API
+	if d.Get("wait_for_record").(bool) {
+		settings := propagationSettings{Timeout: d.Timeout(schema.TimeoutCreate), PollInterval: defaultPropagationPollInterval}
+		for _, nameserver := range d.Get("nameservers").([]interface{}) {
+			if nameserver != nil {
+				settings.Nameservers = append(settings.Nameservers, nameserver.(string))
+			}
+		}
+		if err := waitForRecordPropagation(ctx, settings, zone.GetName(), createdRecord); err != nil {
+			return appendError(diags, "Unable to wait for ACME challenge record", err)
+		}
+	}
+
+	return resourceAcmeChallengeRead(ctx, d, m)
+}
+
+func resourceAcmeChallengeRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(SdkBundle).DnsApiClient
+	var diags diag.Diagnostics
+
+	record, resp, err := c.RecordsApi.GetRecord(ctx, d.Get("zone_id").(string), d.Id()).Execute()
+	if err != nil {
+		// Challenge records are often cleaned up by the certificate tooling itself
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			d.SetId("")
+			return diags
+		}
+		return appendError(diags, "Unable to read ACME challenge record", err)
+	}
+
+	d.Set("name", record.GetName())
+	d.Set("ttl", record.GetTtl())
+
+	return diags
+}
+
+// resourceAcmeChallengeDelete removes the record by its ID only. Other clients may hold challenges with the
+// same name, e.g. for example.com and *.example.com, and after the record was cleaned up even one with the
+// same digest, which must be kept. A record that does not exist anymore is treated as deleted, see
+// deleteDnsRecord.
+func resourceAcmeChallengeDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return deleteDnsRecord(ctx, m, d.Get("zone_id").(string), d.Id())
+}
+
+// acmeChallengeName returns the name of the DNS-01 challenge record for a domain. Wildcard certificates are
+// validated with the challenge record of the base domain.
+func acmeChallengeName(domain string) string {
+	return acmeChallengeLabel + "." + strings.TrimPrefix(normalizeDomainName(domain), "*.")
+}
@@ -0,0 +1,159 @@
+//go:build all || dns
+
+package ionosdeveloper
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/miekg/dns"
+
+	dnsSdk "github.com/ionos-developer/dns-sdk-go"
+)
+
+func TestAccAcmeChallenge(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acmeChallenge,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ionosdeveloper_acme_challenge.c", "name", "_acme-challenge.test-acc."+testZoneName),
+					resource.TestCheckResourceAttrPair("ionosdeveloper_acme_challenge.c", "zone_id", "data.ionosdeveloper_dns_zone.z", "id"),
+					resource.TestCheckResourceAttr("ionosdeveloper_acme_challenge.c", "ttl", "60"),
+				),
+			},
+			{
+				Config: acmeChallengeWildcard,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ionosdeveloper_acme_challenge.c", "name", "_acme-challenge.test-acc."+testZoneName),
+					resource.TestCheckResourceAttr("ionosdeveloper_acme_challenge.w", "name", "_acme-challenge.test-acc."+testZoneName),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAcmeChallenge_DeleteById(t *testing.T) {
+	var otherId string
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Another client holds a challenge with the same name
+				PreConfig: func() {
+					otherId = createTestRecord(t, "_acme-challenge.test-acc."+testZoneName, dnsSdk.TXT, "8ezSyVjF5n5QYhADuBuAbQMvMBxMR4vkEx3o6gTGjVM")
+				},
+				Config: acmeChallenge,
+			},
+			{
+				Config: zoneConfig(testZoneName),
+				Check:  checkRecordExists(&otherId),
+			},
+		},
+	})
+}
+
+func TestAccAcmeChallenge_WaitForRecord(t *testing.T) {
+	testAccFakeOnly(t)
+	nameserver := startTestNameserver(t, servedFakeRecords)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acmeChallengeWaitForRecord(nameserver.Addr(), "1m"),
+				Check: resource.TestCheckFunc(func(s *terraform.State) error {
+					if nameserver.Queries() == 0 {
+						return fmt.Errorf("the nameserver has not been queried")
+					}
+					return nil
+				}),
+			},
+		},
+	})
+}
+
+func TestAccAcmeChallenge_WaitForRecordTimeout(t *testing.T) {
+	testAccFakeOnly(t)
+	nameserver := startTestNameserver(t, func() []dns.RR { return nil })
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      acmeChallengeWaitForRecord(nameserver.Addr(), "2s"),
+				ExpectError: regexp.MustCompile(`the TXT record _acme-challenge\.test-acc\..* is not served by`),
+			},
+		},
+	})
+}
+
+func TestAcmeChallengeName(t *testing.T) {
+	cases := map[string]string{
+		"example.com":    "_acme-challenge.example.com",
+		"*.Example.com.": "_acme-challenge.example.com",
+	}
+
+	for domain, expected := range cases {
+		if actual := acmeChallengeName(domain); actual != expected {
+			t.Errorf("acmeChallengeName(%q): expected %q, got %q", domain, expected, actual)
+		}
+	}
+}
+
+var acmeChallenge = zoneConfig(testZoneName) + `
+resource ionosdeveloper_acme_challenge c {
+  domain = "test-acc.${data.ionosdeveloper_dns_zone.z.name}"
+  digest = "LoqXcYV8q5ONbJQxbmR7SCTNo3tiAXDfowyjxAjEuX0"
+}`
+
+var acmeChallengeWildcard = acmeChallenge + `
+resource ionosdeveloper_acme_challenge w {
+  domain = "*.test-acc.${data.ionosdeveloper_dns_zone.z.name}"
+  digest = "8ezSyVjF5n5QYhADuBuAbQMvMBxMR4vkEx3o6gTGjVM"
+}`
+
+func acmeChallengeWaitForRecord(nameserver string, timeout string) string {
+	return fmt.Sprintf(`
+resource ionosdeveloper_acme_challenge c {
+  domain          = "test-acc.%s"
+  digest          = "LoqXcYV8q5ONbJQxbmR7SCTNo3tiAXDfowyjxAjEuX0"
+  wait_for_record = true
+  nameservers     = [%q]
+
+  timeouts {
+    create = %q
+  }
+}`, testZoneName, nameserver, timeout)
+}
+
+// checkRecordExists checks that the record with the id exists in the test zone
+func checkRecordExists(id *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		ctx := context.Background()
+		client, err := newDnsApiClientFromEnv(ctx, "terraform-provider-ionosdeveloper/test")
+		if err != nil {
+			return err
+		}
+		zone, err := getZoneWithRecords(ctx, client, testZoneName)
+		if err != nil {
+			return err
+		}
+
+		for _, record := range zone.GetRecords() {
+			if record.GetId() == *id {
+				return nil
+			}
+		}
+		return fmt.Errorf("the record %s does not exist anymore", *id)
+	}
+}
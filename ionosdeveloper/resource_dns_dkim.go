@@ -0,0 +1,145 @@
+package ionosdeveloper
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const dkimLabel = "_domainkey"
+
+var pemHeaders = regexp.MustCompile(`-----[A-Z ]+-----`)
+
+func resourceDnsDkim() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceDnsDkimCreate,
+		ReadContext:   resourceTxtRecordRead,
+		UpdateContext: resourceTxtRecordUpdate,
+		DeleteContext: resourceTxtRecordDelete,
+		CustomizeDiff: resourceDnsDkimCustomizeDiff,
+		Schema: map[string]*schema.Schema{
+			"zone_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+			},
+			"id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"domain": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return normalizeDomainName(old) == normalizeDomainName(new)
+				},
+			},
+			"selector": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^[A-Za-z0-9_-]+(\.[A-Za-z0-9_-]+)*$`), "must be a valid DKIM selector"),
+			},
+			"key_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "rsa",
+				ValidateFunc: validation.StringInSlice([]string{"rsa", "ed25519"}, false),
+			},
+			"public_key": {
+				Type:     schema.TypeString,
+				Required: true,
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return normalizeDkimPublicKey(old) == normalizeDkimPublicKey(new)
+				},
+			},
+			"ttl": {
+				Type:             schema.TypeInt,
+				Required:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(60)),
+			},
+			"disabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"content": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceDnsDkimCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	name := d.Get("selector").(string) + "." + dkimLabel + "." + normalizeDomainName(d.Get("domain").(string))
+	return resourceTxtRecordCreate(ctx, d, m, name)
+}
+
+// resourceDnsDkimCustomizeDiff validates the public key against the key type, which cannot be done by the
+// validation of a single attribute.
+func resourceDnsDkimCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("key_type") || !d.NewValueKnown("public_key") {
+		return d.SetNewComputed("content")
+	}
+
+	if err := validateDkimPublicKey(d.Get("key_type").(string), d.Get("public_key").(string)); err != nil {
+		return err
+	}
+
+	if content := renderDkim(d); content != d.Get("content").(string) {
+		return d.SetNew("content", content)
+	}
+
+	return nil
+}
+
+func renderDkim(d attributeGetter) string {
+	return fmt.Sprintf("v=DKIM1; k=%s; p=%s", d.Get("key_type").(string), normalizeDkimPublicKey(d.Get("public_key").(string)))
+}
+
+// normalizeDkimPublicKey accepts PEM encoded keys as well as base64 encoded keys containing whitespace.
+func normalizeDkimPublicKey(key string) string {
+	return strings.Join(strings.Fields(pemHeaders.ReplaceAllString(key, "")), "")
+}
+
+func validateDkimPublicKey(keyType string, key string) error {
+	der, err := base64.StdEncoding.DecodeString(normalizeDkimPublicKey(key))
+	if err != nil {
+		return fmt.Errorf("public_key must be base64 encoded: %v", err)
+	}
+
+	switch keyType {
+	case "rsa":
+		publicKey, err := x509.ParsePKIXPublicKey(der)
+		if err != nil {
+			return fmt.Errorf("public_key is not a valid RSA public key: %v", err)
+		}
+		if _, ok := publicKey.(*rsa.PublicKey); !ok {
+			return fmt.Errorf("public_key is a %T, expected an RSA public key", publicKey)
+		}
+	case "ed25519":
+		// RFC 8463 publishes the raw key instead of a SubjectPublicKeyInfo structure
+		if len(der) != ed25519.PublicKeySize {
+			return fmt.Errorf("public_key must be a raw Ed25519 key of %d bytes, got %d bytes", ed25519.PublicKeySize, len(der))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+//go:build all || dns
+
+package ionosdeveloper
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+var testDkimRsaPublicKey = generateTestDkimRsaPublicKey()
+
+func TestAccDnsDkim(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: dkim,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ionosdeveloper_dns_dkim.d", "name", "mail._domainkey.test-acc."+testZoneName),
+					resource.TestCheckResourceAttr("ionosdeveloper_dns_dkim.d", "content", "v=DKIM1; k=rsa; p="+testDkimRsaPublicKey),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDnsDkim_Validations(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      dkimWrongKeyType,
+				ExpectError: regexp.MustCompile("raw Ed25519 key"),
+			},
+		},
+	})
+}
+
+func TestValidateDkimPublicKey(t *testing.T) {
+	ed25519Key, _, _ := ed25519.GenerateKey(rand.Reader)
+	rawEd25519Key := base64.StdEncoding.EncodeToString(ed25519Key)
+
+	der, _ := base64.StdEncoding.DecodeString(testDkimRsaPublicKey)
+	pemRsaKey := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+	cases := []struct {
+		keyType string
+		key     string
+		valid   bool
+	}{
+		{"rsa", testDkimRsaPublicKey, true},
+		{"rsa", pemRsaKey, true},
+		{"rsa", rawEd25519Key, false},
+		{"rsa", "not base64!", false},
+		{"ed25519", rawEd25519Key, true},
+		{"ed25519", testDkimRsaPublicKey, false},
+	}
+
+	for _, c := range cases {
+		if err := validateDkimPublicKey(c.keyType, c.key); (err == nil) != c.valid {
+			t.Errorf("validateDkimPublicKey(%q, %q): expected valid=%v, got %v", c.keyType, c.key, c.valid, err)
+		}
+	}
+
+	if normalizeDkimPublicKey(pemRsaKey) != testDkimRsaPublicKey {
+		t.Errorf("expected the PEM key to be normalized to the base64 key")
+	}
+}
+
+func TestQuoteTxtContent(t *testing.T) {
+	value := strings.Repeat("a", 300)
+	quoted := quoteTxtContent(value)
+
+	if expected := `"` + strings.Repeat("a", 255) + `" "` + strings.Repeat("a", 45) + `"`; quoted != expected {
+		t.Errorf("unexpected quoted content %q", quoted)
+	}
+	if unquoteTxtContent(quoted) != value {
+		t.Errorf("expected unquoting to restore the value")
+	}
+}
+
+func generateTestDkimRsaPublicKey() string {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		panic(err)
+	}
+
+	return base64.StdEncoding.EncodeToString(der)
+}
+
+var dkim = zoneConfig(testZoneName) + `
+resource ionosdeveloper_dns_dkim d {
+  zone_id    = data.ionosdeveloper_dns_zone.z.id
+  domain     = "test-acc.${data.ionosdeveloper_dns_zone.z.name}"
+  selector   = "mail"
+  public_key = "` + testDkimRsaPublicKey + `"
+  ttl        = 3600
+}`
+
+var dkimWrongKeyType = zoneConfig(testZoneName) + `
+resource ionosdeveloper_dns_dkim d {
+  zone_id    = data.ionosdeveloper_dns_zone.z.id
+  domain     = "test-acc.${data.ionosdeveloper_dns_zone.z.name}"
+  selector   = "mail"
+  key_type   = "ed25519"
+  public_key = "` + testDkimRsaPublicKey + `"
+  ttl        = 3600
+}`
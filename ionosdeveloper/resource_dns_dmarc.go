@@ -0,0 +1,181 @@
+package ionosdeveloper
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const dmarcLabel = "_dmarc"
+
+var dmarcPolicies = []string{"none", "quarantine", "reject"}
+
+var dmarcAlignments = map[string]string{
+	"relaxed": "r",
+	"strict":  "s",
+}
+
+func resourceDnsDmarc() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceDnsDmarcCreate,
+		ReadContext:   resourceTxtRecordRead,
+		UpdateContext: resourceTxtRecordUpdate,
+		DeleteContext: resourceTxtRecordDelete,
+		CustomizeDiff: resourceDnsDmarcCustomizeDiff,
+		Schema: map[string]*schema.Schema{
+			"zone_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+			},
+			"id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"domain": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return normalizeDomainName(old) == normalizeDomainName(new)
+				},
+			},
+			"policy": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice(dmarcPolicies, false),
+			},
+			"subdomain_policy": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(dmarcPolicies, false),
+			},
+			"rua": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateDmarcReportAddress,
+				},
+			},
+			"ruf": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateDmarcReportAddress,
+				},
+			},
+			"pct": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          100,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntBetween(0, 100)),
+			},
+			"adkim": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "relaxed",
+				ValidateFunc: validation.StringInSlice([]string{"relaxed", "strict"}, false),
+			},
+			"aspf": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "relaxed",
+				ValidateFunc: validation.StringInSlice([]string{"relaxed", "strict"}, false),
+			},
+			"ttl": {
+				Type:             schema.TypeInt,
+				Required:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(60)),
+			},
+			"disabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"content": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceDnsDmarcCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return resourceTxtRecordCreate(ctx, d, m, dmarcLabel+"."+normalizeDomainName(d.Get("domain").(string)))
+}
+
+func resourceDnsDmarcCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	for _, key := range []string{"policy", "subdomain_policy", "rua", "ruf", "pct", "adkim", "aspf"} {
+		if !d.NewValueKnown(key) {
+			return d.SetNewComputed("content")
+		}
+	}
+
+	if content := renderDmarc(d); content != d.Get("content").(string) {
+		return d.SetNew("content", content)
+	}
+
+	return nil
+}
+
+// renderDmarc only renders the tags that differ from the defaults of RFC 7489, which keeps the record short.
+func renderDmarc(d attributeGetter) string {
+	tags := []string{"v=DMARC1", "p=" + d.Get("policy").(string)}
+
+	if subdomainPolicy := d.Get("subdomain_policy").(string); subdomainPolicy != "" {
+		tags = append(tags, "sp="+subdomainPolicy)
+	}
+	if pct := d.Get("pct").(int); pct != 100 {
+		tags = append(tags, fmt.Sprintf("pct=%d", pct))
+	}
+	if rua := d.Get("rua").([]interface{}); len(rua) > 0 {
+		tags = append(tags, "rua="+dmarcReportUris(rua))
+	}
+	if ruf := d.Get("ruf").([]interface{}); len(ruf) > 0 {
+		tags = append(tags, "ruf="+dmarcReportUris(ruf))
+	}
+	if adkim := dmarcAlignments[d.Get("adkim").(string)]; adkim != "r" {
+		tags = append(tags, "adkim="+adkim)
+	}
+	if aspf := dmarcAlignments[d.Get("aspf").(string)]; aspf != "r" {
+		tags = append(tags, "aspf="+aspf)
+	}
+
+	return strings.Join(tags, "; ")
+}
+
+// dmarcReportUris accepts plain mail addresses as well as mailto URIs
+func dmarcReportUris(addresses []interface{}) string {
+	uris := make([]string, len(addresses))
+	for i, address := range addresses {
+		uris[i] = "mailto:" + strings.TrimPrefix(address.(string), "mailto:")
+	}
+	return strings.Join(uris, ",")
+}
+
+func validateDmarcReportAddress(i interface{}, k string) ([]string, []error) {
+	value, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+
+	address := strings.TrimPrefix(value, "mailto:")
+	if parsed, err := mail.ParseAddress(address); err != nil || parsed.Address != address || strings.ContainsAny(address, ",;!") {
+		return nil, []error{fmt.Errorf("expected %s to be a mail address or mailto URI, got: %s", k, value)}
+	}
+
+	return nil, nil
+}
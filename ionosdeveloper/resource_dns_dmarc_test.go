@@ -0,0 +1,90 @@
+//go:build all || dns
+
+package ionosdeveloper
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDnsDmarc(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: dmarc,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ionosdeveloper_dns_dmarc.d", "name", "_dmarc.test-acc."+testZoneName),
+					resource.TestCheckResourceAttr("ionosdeveloper_dns_dmarc.d", "content", "v=DMARC1; p=none; rua=mailto:dmarc@example.com"),
+				),
+			},
+			{
+				Config: dmarcUpdated,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ionosdeveloper_dns_dmarc.d", "content", "v=DMARC1; p=reject; sp=quarantine; pct=50; rua=mailto:a@example.com,mailto:b@example.com; adkim=s; aspf=s"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDnsDmarc_Validations(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      dmarcInvalidRua,
+				ExpectError: regexp.MustCompile("mail address"),
+			},
+		},
+	})
+}
+
+func TestValidateDmarcReportAddress(t *testing.T) {
+	for _, valid := range []string{"dmarc@example.com", "mailto:dmarc@example.com"} {
+		if _, errs := validateDmarcReportAddress(valid, "rua"); len(errs) > 0 {
+			t.Errorf("expected %q to be valid, got %v", valid, errs)
+		}
+	}
+
+	for _, invalid := range []string{"example.com", "Name <dmarc@example.com>", "a@example.com,b@example.com"} {
+		if _, errs := validateDmarcReportAddress(invalid, "rua"); len(errs) == 0 {
+			t.Errorf("expected %q to be invalid", invalid)
+		}
+	}
+}
+
+var dmarc = zoneConfig(testZoneName) + `
+resource ionosdeveloper_dns_dmarc d {
+  zone_id = data.ionosdeveloper_dns_zone.z.id
+  domain  = "test-acc.${data.ionosdeveloper_dns_zone.z.name}"
+  policy  = "none"
+  rua     = ["dmarc@example.com"]
+  ttl     = 3600
+}`
+
+var dmarcUpdated = zoneConfig(testZoneName) + `
+resource ionosdeveloper_dns_dmarc d {
+  zone_id          = data.ionosdeveloper_dns_zone.z.id
+  domain           = "test-acc.${data.ionosdeveloper_dns_zone.z.name}"
+  policy           = "reject"
+  subdomain_policy = "quarantine"
+  pct              = 50
+  rua              = ["mailto:a@example.com", "b@example.com"]
+  adkim            = "strict"
+  aspf             = "strict"
+  ttl              = 3600
+}`
+
+var dmarcInvalidRua = zoneConfig(testZoneName) + `
+resource ionosdeveloper_dns_dmarc d {
+  zone_id = data.ionosdeveloper_dns_zone.z.id
+  domain  = "test-acc.${data.ionosdeveloper_dns_zone.z.name}"
+  policy  = "none"
+  rua     = ["example.com"]
+  ttl     = 3600
+}`
@@ -2,209 +2,778 @@ package ionosdeveloper
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net"
+	"net/http"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	fwdiag "github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"golang.org/x/net/idna"
 
 	dnsSdk "github.com/ionos-developer/dns-sdk-go"
 )
 
-func resourceDnsRecord() *schema.Resource {
-	return &schema.Resource{
-		CreateContext: resourceDnsRecordCreate,
-		ReadContext:   resourceDnsRecordRead,
-		UpdateContext: resourceDnsRecordUpdate,
-		DeleteContext: resourceDnsRecordDelete,
-		Schema: map[string]*schema.Schema{
-			"zone_id": {
-				Type:         schema.TypeString,
-				Required:     true,
-				ForceNew:     true,
-				ValidateFunc: validation.NoZeroValues,
+// recordTypes are the record types supported by the API
+var recordTypes = []string{"A", "AAAA", "CNAME", "MX", "NS", "SOA", "SRV", "TXT", "CAA"}
+
+// dnsRecordResource is the only resource served with terraform-plugin-framework so far, see
+// ProviderServerFactory. It shares the API calls with the SDKv2 resources, see createDnsRecord.
+type dnsRecordResource struct {
+	// meta is the SdkBundle of the provider, or nil before the provider is configured
+	meta interface{}
+}
+
+type dnsRecordModel struct {
+	ZoneId             types.String `tfsdk:"zone_id"`
+	ZoneName           types.String `tfsdk:"zone_name"`
+	Id                 types.String `tfsdk:"id"`
+	Name               types.String `tfsdk:"name"`
+	Fqdn               types.String `tfsdk:"fqdn"`
+	Type               types.String `tfsdk:"type"`
+	Content            types.String `tfsdk:"content"`
+	Ttl                types.Int64  `tfsdk:"ttl"`
+	Prio               types.Int64  `tfsdk:"prio"`
+	Disabled           types.Bool   `tfsdk:"disabled"`
+	WaitForPropagation types.Object `tfsdk:"wait_for_propagation"`
+	OnConflict         types.String `tfsdk:"on_conflict"`
+	DeletionProtection types.Bool   `tfsdk:"deletion_protection"`
+}
+
+func newDnsRecordResource() resource.Resource {
+	return &dnsRecordResource{}
+}
+
+func (r *dnsRecordResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_dns_record"
+}
+
+// Schema is at version 1, version 0 is the schema of the SDKv2 resource, see upgradeDnsRecordState
+func (r *dnsRecordResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version: 1,
+		Attributes: map[string]schema.Attribute{
+			// Without zone_id and zone_name, the zone is inferred from the name, see planRecordZone. Changing
+			// the zone replaces the record, see ModifyPlan.
+			"zone_id": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+					stringvalidator.ConflictsWith(path.MatchRoot("zone_name")),
+				},
 			},
-			"id": {
-				Type:     schema.TypeString,
+			"zone_name": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+					stringvalidator.ConflictsWith(path.MatchRoot("zone_id")),
+				},
+			},
+			"id": schema.StringAttribute{
 				Computed: true,
 			},
-			"name": {
-				Type:     schema.TypeString,
+			"name": schema.StringAttribute{
 				Required: true,
-				ForceNew: true,
-				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
-					if strings.EqualFold(old, new) {
-						return true
-					}
-
-					record := dnsSdk.NewRecord()
-					record.SetName(new)
-					record.SetType(getRecordType(d.Get("type")))
-					record.SetContent(d.Get("content").(string))
-					normalized, _, err := sdkBundle.DnsApiClient.RecordsApi.NormalizeRecord(context.TODO()).Record(*record).Execute()
-
-					if err != nil {
-						fmt.Printf("Error getting the normalized content from API")
-						return false
-					}
-
-					return normalized.GetName() == old
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIf(requiresReplaceIfRenamed,
+						"Changing the name replaces the record, unless the name refers to the same record.",
+						"Changing the name replaces the record, unless the name refers to the same record."),
 				},
 			},
-			"type": {
-				Type:     schema.TypeString,
+			"fqdn": schema.StringAttribute{
+				Computed: true,
+			},
+			"type": schema.StringAttribute{
 				Required: true,
-				ForceNew: true,
-				StateFunc: func(v interface{}) string {
-					value := strings.ToUpper(v.(string))
-					return value
+				Validators: []validator.String{
+					stringvalidator.OneOfCaseInsensitive(recordTypes...),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIf(requiresReplaceIfRetyped,
+						"Changing the type replaces the record, changing its case does not.",
+						"Changing the type replaces the record, changing its case does not."),
 				},
-				ValidateFunc: validation.StringInSlice([]string{"A", "AAAA", "CNAME", "MX", "NS", "SOA", "SRV", "TXT", "CAA"}, true),
 			},
-			"content": {
-				Type:     schema.TypeString,
+			"content": schema.StringAttribute{
 				Required: true,
-				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
-					if strings.TrimSpace(old) == strings.TrimSpace(new) {
-						return true
-					}
-
-					recordType := getRecordType(d.Get("type"))
-
-					record := dnsSdk.NewRecord()
-					record.SetType(recordType)
-					record.SetContent(new)
-					normalized, _, err := sdkBundle.DnsApiClient.RecordsApi.NormalizeRecord(context.TODO()).Record(*record).Execute()
-
-					if err != nil {
-						fmt.Printf("Error getting the normalized content from API")
-						return false
-					}
-
-					return normalized.GetContent() == old
+			},
+			// ttl and disabled fall back to the defaults of the provider, see ModifyPlan
+			"ttl": schema.Int64Attribute{
+				Optional: true,
+				Computed: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(60),
+				},
+			},
+			// prio is only sent to the API if it is configured
+			"prio": schema.Int64Attribute{
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.Between(0, 65535),
 				},
 			},
-			"ttl": {
-				Type:             schema.TypeInt,
-				Required:         true,
-				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(60)),
+			"disabled": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
 			},
-			"prio": {
-				Type:             schema.TypeInt,
-				Optional:         true,
-				ValidateDiagFunc: validation.ToDiagFunc(validation.IntBetween(0, 65535)),
-				Default:          0,
+			"wait_for_propagation": propagationSchema(),
+			// on_conflict only affects the creation of the resource, see adoptDnsRecord
+			"on_conflict": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(onConflictError),
+				Validators: []validator.String{
+					stringvalidator.OneOf(onConflictError, onConflictAdopt, onConflictOverwrite),
+				},
 			},
-			"disabled": {
-				Type:     schema.TypeBool,
+			// The protection is read from the state, see checkDeletionProtection
+			"deletion_protection": schema.BoolAttribute{
 				Optional: true,
-				Default:  false,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
 			},
 		},
 	}
 }
 
-func resourceDnsRecordCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(SdkBundle).DnsApiClient
-	var diags diag.Diagnostics
+func (r *dnsRecordResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if bundle, ok := req.ProviderData.(SdkBundle); ok {
+		r.meta = bundle
+	}
+}
+
+// requiresReplaceIfRenamed keeps the record if the new name refers to the same record, e.g. a name relative
+// to the zone instead of the fully qualified name
+func requiresReplaceIfRenamed(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+	var zoneName types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("zone_name"), &zoneName)...)
+
+	resp.RequiresReplace = req.PlanValue.IsUnknown() ||
+		!sameRecordName(req.StateValue.ValueString(), req.PlanValue.ValueString(), zoneName.ValueString())
+}
+
+func requiresReplaceIfRetyped(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+	resp.RequiresReplace = req.PlanValue.IsUnknown() || !strings.EqualFold(req.StateValue.ValueString(), req.PlanValue.ValueString())
+}
+
+// sameRecordName reports whether two names, relative to the zone or fully qualified, refer to the same record
+func sameRecordName(name string, other string, zoneName string) bool {
+	return comparableRecordName(expandRecordName(name, zoneName)) == comparableRecordName(expandRecordName(other, zoneName))
+}
+
+// ModifyPlan plans the zone, and the provider defaults for ttl and disabled if they are not configured.
+// Without a default ttl, the ttl of new records is chosen by the API and kept afterwards. Conflicts with
+// other records are reported at plan time, see checkPlannedRecord.
+func (r *dnsRecordResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if r.meta == nil {
+		return
+	}
+
+	// Deleting a protected record is refused by Delete
+	if req.Plan.Raw.IsNull() {
+		var state dnsRecordModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if !resp.Diagnostics.HasError() {
+			r.meta.(SdkBundle).PlannedRecords.release(state.ZoneId.ValueString(), state.Id.ValueString())
+		}
+		return
+	}
+
+	var plan, config dnsRecordModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	var state *dnsRecordModel
+	if !req.State.Raw.IsNull() {
+		state = &dnsRecordModel{}
+		resp.Diagnostics.Append(req.State.Get(ctx, state)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := planRecordZone(ctx, r.meta.(SdkBundle).Zones, &plan, config, state); err != nil {
+		resp.Diagnostics.AddError("Unable to plan the zone of the record", err.Error())
+		return
+	}
+
+	replaced := false
+	if state != nil {
+		if !plan.ZoneId.Equal(state.ZoneId) {
+			resp.RequiresReplace.Append(path.Root("zone_id"))
+		}
+		replaced = !plan.ZoneId.Equal(state.ZoneId) ||
+			plan.Name.IsUnknown() || !sameRecordName(state.Name.ValueString(), plan.Name.ValueString(), state.ZoneName.ValueString()) ||
+			plan.Type.IsUnknown() || !strings.EqualFold(state.Type.ValueString(), plan.Type.ValueString())
+
+		if err := checkDeletionProtection(*state, plan, replaced); err != nil {
+			resp.Diagnostics.AddError("Record is protected by deletion_protection", err.Error())
+			return
+		}
+		if replaced {
+			r.meta.(SdkBundle).PlannedRecords.release(state.ZoneId.ValueString(), state.Id.ValueString())
+		}
+	}
+
+	// The values computed by the API are kept unless the record is replaced
+	if state != nil && !replaced {
+		plan.Id = state.Id
+		plan.Fqdn = state.Fqdn
+	} else {
+		plan.Id = types.StringUnknown()
+		plan.Fqdn = types.StringUnknown()
+	}
+
+	defaults := r.meta.(SdkBundle).RecordDefaults
+
+	if config.Ttl.IsNull() {
+		switch {
+		case defaults.TTL != 0:
+			plan.Ttl = types.Int64Value(int64(defaults.TTL))
+		case state != nil && !replaced:
+			plan.Ttl = state.Ttl
+		default:
+			plan.Ttl = types.Int64Unknown()
+		}
+	}
 
-	record := createRecord(d)
-	zoneId := d.Get("zone_id").(string)
+	if config.Disabled.IsNull() {
+		plan.Disabled = types.BoolValue(defaults.Disabled)
+	}
+
+	// Conflicts that depend on unknown values are left to the API
+	if !plan.ZoneId.IsUnknown() && !plan.ZoneName.IsUnknown() && !plan.Name.IsUnknown() && !plan.Type.IsUnknown() && !plan.Content.IsUnknown() {
+		id := ""
+		if state != nil {
+			id = state.Id.ValueString()
+		}
+		record := newPlannedRecord(id, plan.Name.ValueString(), plan.ZoneName.ValueString(), plan.Type.ValueString(), plan.Content.ValueString())
+		changed := state == nil || replaced || !plan.Content.Equal(state.Content)
+		adopting := state == nil && plan.OnConflict.ValueString() != onConflictError
+
+		err := checkPlannedRecord(ctx, r.meta, plan.ZoneId.ValueString(), plan.ZoneName.ValueString(), record, changed, adopting)
+		if err != nil {
+			resp.Diagnostics.AddError("Conflicting DNS record", err.Error())
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+// checkDeletionProtection fails the plan if it replaces a record protected by deletion_protection. Like in
+// Delete, the protection is taken from the state, so that turning it off in the same apply does not allow the
+// replacement.
+func checkDeletionProtection(state dnsRecordModel, plan dnsRecordModel, replaced bool) error {
+	if !replaced || !state.DeletionProtection.ValueBool() {
+		return nil
+	}
+
+	changed := "type"
+	switch {
+	case !plan.ZoneId.Equal(state.ZoneId):
+		changed = "zone_id"
+	case plan.Name.IsUnknown() || !sameRecordName(state.Name.ValueString(), plan.Name.ValueString(), state.ZoneName.ValueString()):
+		changed = "name"
+	}
+	return fmt.Errorf("the record %s (%s) is protected by deletion_protection and cannot be replaced to change %s, "+
+		"set deletion_protection to false in a separate apply first", state.Fqdn.ValueString(), state.Id.ValueString(), changed)
+}
+
+// planRecordZone plans zone_id from zone_name or, if neither is configured, from the zone whose name is the
+// longest suffix of the record name. Changing the zone this way replaces the record. A configured zone_name
+// is planned as written.
+func planRecordZone(ctx context.Context, zones *zoneCache, plan *dnsRecordModel, config dnsRecordModel, state *dnsRecordModel) error {
+	switch {
+	case !config.ZoneId.IsNull():
+		// zone_name is only informational here, an unknown zone is reported by the API
+		if plan.ZoneId.IsUnknown() {
+			plan.ZoneName = types.StringUnknown()
+			return nil
+		}
+		if state != nil && plan.ZoneId.Equal(state.ZoneId) {
+			plan.ZoneName = state.ZoneName
+			return nil
+		}
+		zone, found, err := zones.findById(ctx, plan.ZoneId.ValueString())
+		if err != nil || !found {
+			return err
+		}
+		plan.ZoneName = types.StringValue(zone.GetName())
+		return nil
+	case !config.ZoneName.IsNull():
+		if plan.ZoneName.IsUnknown() {
+			plan.ZoneId = types.StringUnknown()
+			return nil
+		}
+	default:
+		if plan.Name.IsUnknown() {
+			plan.ZoneId = types.StringUnknown()
+			plan.ZoneName = types.StringUnknown()
+			return nil
+		}
+		if name := plan.Name.ValueString(); name == "@" || !strings.Contains(strings.TrimSuffix(name, "."), ".") {
+			return fmt.Errorf("the zone of the relative name %q must be set with zone_id or zone_name", name)
+		}
+	}
 
-	createdRecords, _, err := client.RecordsApi.CreateRecords(context.Background(), zoneId).Record([]dnsSdk.Record{*record}).Execute()
+	zone, err := resolveRecordZone(ctx, zones, "", config.ZoneName.ValueString(), plan.Name.ValueString())
 	if err != nil {
-		return appendError(diags, "Unable to create zone record", err)
+		return err
 	}
 
-	d.SetId(*createdRecords[0].Id)
+	plan.ZoneId = types.StringValue(zone.GetId())
+	if config.ZoneName.IsNull() {
+		plan.ZoneName = types.StringValue(zone.GetName())
+	}
+	return nil
+}
+
+func (r *dnsRecordResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan dnsRecordModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The zone could not be planned if zone_id, zone_name or name were unknown
+	if plan.ZoneId.IsUnknown() || plan.ZoneName.IsUnknown() {
+		zone, err := resolveRecordZone(ctx, r.meta.(SdkBundle).Zones, plan.ZoneId.ValueString(), plan.ZoneName.ValueString(), plan.Name.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to find the zone of the record", err.Error())
+			return
+		}
+		plan.ZoneId = types.StringValue(zone.GetId())
+		if plan.ZoneName.IsUnknown() {
+			plan.ZoneName = types.StringValue(zone.GetName())
+		}
+	}
+
+	zoneId := plan.ZoneId.ValueString()
+	record := newDnsRecord(plan)
+
+	var created *dnsSdk.RecordResponse
+	var diags diag.Diagnostics
+	if onConflict := plan.OnConflict.ValueString(); onConflict != onConflictError {
+		existing, sameContent, err := findConflictingRecord(ctx, r.meta, zoneId, record, onConflict == onConflictOverwrite)
+		if err != nil {
+			resp.Diagnostics.Append(frameworkDiagnostics(appendError(nil, "Unable to search for existing records", err))...)
+			return
+		}
+		if existing != nil {
+			created, diags = adoptDnsRecord(ctx, r.meta, zoneId, onConflict, existing, sameContent, record)
+		}
+	}
+
+	// The created record is complete, reading it again would cost a request per record
+	if created == nil && !diags.HasError() {
+		created, diags = createDnsRecord(ctx, r.meta, zoneId, record)
+	}
+	resp.Diagnostics.Append(frameworkDiagnostics(diags)...)
+	if diags.HasError() {
+		return
+	}
+
+	plan.setComputedValues(created)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 
-	return resourceDnsRecordRead(ctx, d, m)
+	r.waitForRecord(ctx, plan, created, &resp.Diagnostics)
 }
 
-func createRecord(d *schema.ResourceData) *dnsSdk.Record {
+// newDnsRecord returns the record to create for a plan
+func newDnsRecord(plan dnsRecordModel) *dnsSdk.Record {
 	record := dnsSdk.NewRecord()
 
-	record.SetName(d.Get("name").(string))
-	record.SetType(getRecordType(d.Get("type")))
-	record.SetContent(d.Get("content").(string))
-	record.SetTtl(int32(d.Get("ttl").(int)))
-	record.SetPrio(int32(d.Get("prio").(int)))
-	record.SetDisabled(d.Get("disabled").(bool))
+	record.SetName(expandRecordName(plan.Name.ValueString(), plan.ZoneName.ValueString()))
+	record.SetType(getRecordType(plan.Type.ValueString()))
+	record.SetContent(plan.Content.ValueString())
+	if !plan.Ttl.IsNull() && !plan.Ttl.IsUnknown() {
+		record.SetTtl(int32(plan.Ttl.ValueInt64()))
+	}
+	// prio is only meaningful for MX and SRV records, the API should not receive it unless it is configured
+	if !plan.Prio.IsNull() {
+		record.SetPrio(int32(plan.Prio.ValueInt64()))
+	}
+	record.SetDisabled(plan.Disabled.ValueBool())
 
 	return record
 }
 
-func resourceDnsRecordRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(SdkBundle).DnsApiClient
-	var diags diag.Diagnostics
+// setComputedValues sets the values that are only known once the record has been written. The other values
+// are stored as planned.
+func (m *dnsRecordModel) setComputedValues(record *dnsSdk.RecordResponse) {
+	if m.Id.IsUnknown() {
+		m.Id = types.StringValue(record.GetId())
+	}
+	if m.Fqdn.IsUnknown() {
+		m.Fqdn = types.StringValue(record.GetName())
+	}
+	if m.Ttl.IsUnknown() {
+		m.Ttl = types.Int64Value(int64(record.GetTtl()))
+	}
+}
 
-	zoneId := d.Get("zone_id").(string)
-	recordId := d.Id()
+// waitForRecord waits for the record to be served if wait_for_propagation is set, see
+// waitForRecordPropagation. The record is kept in the state if waiting fails, Terraform taints it then.
+func (r *dnsRecordResource) waitForRecord(ctx context.Context, plan dnsRecordModel, record *dnsSdk.RecordResponse, diagnostics *fwdiag.Diagnostics) {
+	settings, ok, diags := propagationSettingsFromObject(ctx, plan.WaitForPropagation)
+	diagnostics.Append(diags...)
+	if !ok || diags.HasError() {
+		return
+	}
 
-	record, _, err := c.RecordsApi.GetRecord(context.Background(), zoneId, recordId).Execute()
-	if err != nil {
-		return appendError(diags, "Unable to read record", err)
+	if err := waitForRecordPropagation(ctx, settings, plan.ZoneName.ValueString(), record); err != nil {
+		diagnostics.AddError("Unable to wait for the propagation of the record", err.Error())
 	}
+}
 
-	d.Set("name", *record.Name)
-	d.Set("type", *record.Type)
-	d.Set("content", *record.Content)
-	d.Set("ttl", *record.Ttl)
-	if record.Prio != nil {
-		d.Set("prio", *record.Prio)
+func (r *dnsRecordResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state dnsRecordModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
-	d.Set("disabled", *record.Disabled)
 
-	return diags
+	record, diags := readDnsRecord(ctx, r.meta, state.ZoneId.ValueString(), state.Id.ValueString())
+	resp.Diagnostics.Append(frameworkDiagnostics(diags)...)
+	if diags.HasError() {
+		return
+	}
+
+	zone, found, err := r.meta.(SdkBundle).Zones.findById(ctx, state.ZoneId.ValueString())
+	if err == nil && found && normalizeDomainName(zone.GetName()) != normalizeDomainName(state.ZoneName.ValueString()) {
+		state.ZoneName = types.StringValue(zone.GetName())
+	}
+	r.setRecordState(ctx, &state, record)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
-func resourceDnsRecordUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(SdkBundle).DnsApiClient
-	var diags diag.Diagnostics
+// setRecordState sets the state from the record returned by the API. The name, type and content are kept as
+// configured as long as they describe the same record, e.g. a name relative to the zone or a TXT record
+// without quotes.
+func (r *dnsRecordResource) setRecordState(ctx context.Context, state *dnsRecordModel, record *dnsSdk.RecordResponse) {
+	imported := state.Type.IsNull()
+
+	if !sameRecordName(state.Name.ValueString(), record.GetName(), state.ZoneName.ValueString()) {
+		state.Name = types.StringValue(record.GetName())
+	}
+	state.Fqdn = types.StringValue(record.GetName())
+	if !strings.EqualFold(state.Type.ValueString(), string(record.GetType())) {
+		state.Type = types.StringValue(string(record.GetType()))
+	}
+	if imported || !r.sameRecordContent(ctx, state.Content.ValueString(), record) {
+		state.Content = types.StringValue(record.GetContent())
+	}
+	state.Ttl = types.Int64Value(int64(record.GetTtl()))
+	state.Disabled = types.BoolValue(record.GetDisabled())
 
-	zoneId := d.Get("zone_id").(string)
-	recordId := d.Id()
+	// prio stays null unless it is configured, except for the MX and SRV records that are imported
+	if record.Prio != nil && (!state.Prio.IsNull() || imported && (record.GetType() == dnsSdk.MX || record.GetType() == dnsSdk.SRV)) {
+		state.Prio = types.Int64Value(int64(record.GetPrio()))
+	}
+}
+
+// sameRecordContent reports whether the content in the state describes the content returned by the API,
+// which may be normalized differently
+func (r *dnsRecordResource) sameRecordContent(ctx context.Context, content string, record *dnsSdk.RecordResponse) bool {
+	if comparableRecordContent(record.GetType(), content) == comparableRecordContent(record.GetType(), record.GetContent()) {
+		return true
+	}
+
+	request := dnsSdk.NewRecord()
+	request.SetName(record.GetName())
+	request.SetType(record.GetType())
+	request.SetContent(content)
+	normalized, _, err := r.meta.(SdkBundle).DnsApiClient.RecordsApi.NormalizeRecord(ctx).Record(*request).Execute()
+	if err != nil {
+		tflog.Debug(ctx, "Unable to normalize the content of the record", map[string]interface{}{"error": err.Error()})
+		return false
+	}
+
+	return normalized.GetContent() == record.GetContent()
+}
+
+func (r *dnsRecordResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state dnsRecordModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	recordUpdate := *dnsSdk.NewRecordUpdate()
+	changed, served := false, false
+
+	if !plan.Content.Equal(state.Content) {
+		recordUpdate.SetContent(plan.Content.ValueString())
+		changed, served = true, true
+	}
+
+	if !plan.Ttl.Equal(state.Ttl) {
+		recordUpdate.SetTtl(int32(plan.Ttl.ValueInt64()))
+		changed = true
+	}
+
+	// An unset prio keeps the prio of the record
+	if !plan.Prio.IsNull() && !plan.Prio.Equal(state.Prio) {
+		recordUpdate.SetPrio(int32(plan.Prio.ValueInt64()))
+		changed, served = true, true
+	}
+
+	if !plan.Disabled.Equal(state.Disabled) {
+		recordUpdate.SetDisabled(plan.Disabled.ValueBool())
+		changed, served = true, true
+	}
+
+	// Changing on_conflict, wait_for_propagation or the case of the name or type alone does not require a
+	// request
+	if !changed {
+		resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+		return
+	}
+
+	record, diags := updateDnsRecord(ctx, r.meta, state.ZoneId.ValueString(), state.Id.ValueString(), recordUpdate)
+	resp.Diagnostics.Append(frameworkDiagnostics(diags)...)
+	if diags.HasError() {
+		return
+	}
+
+	plan.Id = types.StringValue(record.GetId())
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+
+	if served {
+		r.waitForRecord(ctx, plan, record, &resp.Diagnostics)
+	}
+}
+
+func (r *dnsRecordResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state dnsRecordModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if state.DeletionProtection.ValueBool() {
+		resp.Diagnostics.AddError("Record is protected by deletion_protection",
+			fmt.Sprintf("The %s record %s (%s) cannot be deleted while deletion_protection is set. Set deletion_protection to false and apply before deleting the record.",
+				state.Type.ValueString(), state.Fqdn.ValueString(), state.Id.ValueString()))
+		return
+	}
+
+	resp.Diagnostics.Append(frameworkDiagnostics(deleteDnsRecord(ctx, r.meta, state.ZoneId.ValueString(), state.Id.ValueString()))...)
+}
+
+// ImportState accepts IDs in the form <zone_id>/<record_id>, since a record can only be read in the context
+// of its zone.
+func (r *dnsRecordResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	parts := strings.Split(req.ID, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		resp.Diagnostics.AddError("Unexpected format of ID",
+			fmt.Sprintf("unexpected format of ID (%s), expected <zone_id>/<record_id>", req.ID))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("zone_id"), parts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), parts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("on_conflict"), onConflictError)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("deletion_protection"), false)...)
+}
+
+func (r *dnsRecordResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {StateUpgrader: upgradeDnsRecordStateV0},
+	}
+}
+
+func upgradeDnsRecordStateV0(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var state map[string]interface{}
+	if req.RawState == nil || json.Unmarshal(req.RawState.JSON, &state) != nil {
+		resp.Diagnostics.AddError("Unable to upgrade the state of the record", "The state of the SDKv2 resource is not valid JSON.")
+		return
+	}
+
+	upgraded, err := json.Marshal(upgradeDnsRecordState(state))
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to upgrade the state of the record", err.Error())
+		return
+	}
+	resp.DynamicValue = &tfprotov6.DynamicValue{JSON: upgraded}
+}
+
+// upgradeDnsRecordState converts the state of the SDKv2 resource: wait_for_propagation was a block stored as
+// a list, and an unset prio was stored as 0. Attributes added since the first release are set to their
+// defaults, or to null for the ones that Read sets.
+func upgradeDnsRecordState(state map[string]interface{}) map[string]interface{} {
+	upgraded := map[string]interface{}{
+		"on_conflict":         onConflictError,
+		"deletion_protection": false,
+	}
+	for _, key := range []string{"zone_id", "zone_name", "id", "name", "fqdn", "type", "content", "ttl", "prio", "disabled", "on_conflict", "deletion_protection"} {
+		if value, ok := state[key]; ok && value != nil {
+			upgraded[key] = value
+		} else if _, ok := upgraded[key]; !ok {
+			upgraded[key] = nil
+		}
+	}
+
+	if prio, ok := upgraded["prio"].(float64); ok && prio == 0 {
+		upgraded["prio"] = nil
+	}
+
+	upgraded["wait_for_propagation"] = nil
+	if blocks, ok := state["wait_for_propagation"].([]interface{}); ok && len(blocks) > 0 {
+		if block, ok := blocks[0].(map[string]interface{}); ok {
+			if nameservers, ok := block["nameservers"].([]interface{}); ok && len(nameservers) == 0 {
+				block["nameservers"] = nil
+			}
+			upgraded["wait_for_propagation"] = block
+		}
+	}
+
+	return upgraded
+}
+
+// lookupIdnaProfile maps names with the transitional processing of IDNA2003 like the API, e.g. ß to ss.
+// idna.Lookup only does so for Go versions before 1.18.
+var lookupIdnaProfile = idna.New(idna.MapForLookup(), idna.Transitional(true))
+
+// comparableRecordName returns a name in the form returned by the API
+func comparableRecordName(name string) string {
+	name = normalizeDomainName(name)
+	if ascii, err := lookupIdnaProfile.ToASCII(name); err == nil {
+		return ascii
+	}
+	return name
+}
 
-	if d.HasChange("content") {
-		recordUpdate.SetContent(d.Get("content").(string))
+// comparableRecordContent strips the differences between the configured content and the content returned
+// by the API that do not change the record, e.g. the quotes of TXT records.
+func comparableRecordContent(recordType dnsSdk.RecordTypes, content string) string {
+	switch recordType {
+	case dnsSdk.TXT:
+		return unquoteTxtContent(content)
+	case dnsSdk.CNAME, dnsSdk.MX, dnsSdk.NS, dnsSdk.SRV:
+		return normalizeDomainName(content)
+	case dnsSdk.A, dnsSdk.AAAA:
+		if ip := net.ParseIP(strings.TrimSpace(content)); ip != nil {
+			return ip.String()
+		}
+		return strings.TrimSpace(content)
+	default:
+		return strings.TrimSpace(content)
 	}
+}
 
-	if d.HasChange("ttl") {
-		recordUpdate.SetTtl(int32(d.Get("ttl").(int)))
+// The functions below implement the API calls for resources that manage a single record identified by
+// zone_id and the resource id, so that convenience resources like ionosdeveloper_dns_spf share the
+// behaviour of ionosdeveloper_dns_record.
+
+// createDnsRecord creates the record in a batch with the records of other resources, see recordBatcher, and
+// returns the created record.
+func createDnsRecord(ctx context.Context, m interface{}, zoneId string, record *dnsSdk.Record) (*dnsSdk.RecordResponse, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	// The ownership marker is created first, so that a record is never left without one
+	unlock := m.(SdkBundle).Ownership.lock(zoneId, ownershipMarkerName(record.GetName(), record.GetType()))
+	defer unlock()
+	if err := claimRecordOwnership(ctx, m, zoneId, record, false); err != nil {
+		return nil, appendError(diags, "Unable to claim the ownership of the record", err)
 	}
 
-	if d.HasChange("prio") {
-		recordUpdate.SetPrio(int32(d.Get("prio").(int)))
+	createdRecord, err := m.(SdkBundle).Batcher.create(ctx, zoneId, *record)
+	m.(SdkBundle).Records.invalidate(zoneId)
+	if err != nil {
+		if releaseErr := releaseRecordOwnership(ctx, m, zoneId, record.GetName(), record.GetType()); releaseErr != nil {
+			tflog.Warn(ctx, releaseErr.Error())
+		}
+		return nil, appendError(diags, "Unable to create zone record", err)
 	}
 
-	if d.HasChange("disabled") {
-		recordUpdate.SetDisabled(d.Get("disabled").(bool))
+	return &createdRecord, diags
+}
+
+// readDnsRecord reads the record from the snapshot of its zone, see recordCache.
+func readDnsRecord(ctx context.Context, m interface{}, zoneId string, recordId string) (*dnsSdk.RecordResponse, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	record, err := m.(SdkBundle).Records.get(ctx, zoneId, recordId)
+	if err != nil {
+		return nil, appendError(diags, "Unable to read record", err)
 	}
 
-	updatedRecord, _, err := c.RecordsApi.UpdateRecord(context.Background(), zoneId, recordId).RecordUpdate(recordUpdate).Execute()
+	return record, diags
+}
+
+// updateDnsRecord returns the updated record. If owner_id is set, records owned by another owner_id are not
+// updated, and records without a marker are claimed first, see recordOwnership.
+func updateDnsRecord(ctx context.Context, m interface{}, zoneId string, recordId string, recordUpdate dnsSdk.RecordUpdate) (*dnsSdk.RecordResponse, diag.Diagnostics) {
+	c := m.(SdkBundle).DnsApiClient
+	var diags diag.Diagnostics
+
+	record, err := checkRecordOwnership(ctx, m, zoneId, recordId)
 	if err != nil {
-		return appendError(diags, "Unable to update record", err)
+		return nil, appendError(diags, "Unable to update record", err)
+	}
+	if err := claimUnmarkedRecord(ctx, m, zoneId, record); err != nil {
+		return nil, appendError(diags, "Unable to claim the ownership of the record", err)
 	}
 
-	d.SetId(*updatedRecord.Id)
+	updatedRecord, _, err := c.RecordsApi.UpdateRecord(ctx, zoneId, recordId).RecordUpdate(recordUpdate).Execute()
+	m.(SdkBundle).Records.invalidate(zoneId)
+	if err != nil {
+		return nil, appendError(diags, "Unable to update record", err)
+	}
 
-	return resourceDnsRecordRead(ctx, d, m)
+	return updatedRecord, diags
 }
 
-func resourceDnsRecordDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+// deleteDnsRecord treats records that do not exist anymore as deleted. If owner_id is set, records owned by
+// another owner_id are not deleted, see recordOwnership.
+func deleteDnsRecord(ctx context.Context, m interface{}, zoneId string, recordId string) diag.Diagnostics {
 	c := m.(SdkBundle).DnsApiClient
 	var diags diag.Diagnostics
 
-	zoneId := d.Get("zone_id").(string)
-	recordId := d.Id()
+	record, err := checkRecordOwnership(ctx, m, zoneId, recordId)
+	if err != nil {
+		return appendError(diags, "Unable to delete record", err)
+	}
+	if record != nil {
+		unlock := m.(SdkBundle).Ownership.lock(zoneId, ownershipMarkerName(record.GetName(), record.GetType()))
+		defer unlock()
+	}
 
-	if _, err := c.RecordsApi.DeleteRecord(context.Background(), zoneId, recordId).Execute(); err != nil {
+	resp, err := c.RecordsApi.DeleteRecord(ctx, zoneId, recordId).Execute()
+	m.(SdkBundle).Records.invalidate(zoneId)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return diags
+		}
 		return appendError(diags, "Unable to delete record", err)
 	}
 
+	// The marker is kept as long as it owns other records
+	if record != nil {
+		if err := releaseRecordOwnership(ctx, m, zoneId, record.GetName(), record.GetType()); err != nil {
+			return appendError(diags, "Unable to release the ownership of the record", err)
+		}
+	}
+
 	return diags
 }
 
@@ -3,18 +3,72 @@
 package ionosdeveloper
 
 import (
+	"context"
 	"fmt"
+	"log"
+	"net/http"
+	"reflect"
 	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/miekg/dns"
+
+	dnsSdk "github.com/ionos-developer/dns-sdk-go"
 )
 
+// testAccRecordName matches the names of the records created by the acceptance tests relative to the test
+// zone, e.g. test-acc., ss.test-acc. or _dmarc.test-acc2.
+var testAccRecordName = regexp.MustCompile(`(^|\.)test-acc[0-9]*\.$`)
+
+func init() {
+	resource.AddTestSweepers("ionosdeveloper_dns_record", &resource.Sweeper{
+		Name: "ionosdeveloper_dns_record",
+		F:    sweepDnsRecords,
+	})
+}
+
+func sweepDnsRecords(region string) error {
+	client, err := newDnsApiClientFromEnv(context.Background(), "terraform-provider-ionosdeveloper/sweeper")
+	if err != nil {
+		return err
+	}
+
+	return sweepZoneRecords(context.Background(), client, testZoneName)
+}
+
+func sweepZoneRecords(ctx context.Context, client *dnsSdk.APIClient, zoneName string) error {
+	zone, err := getZoneWithRecords(ctx, client, zoneName)
+	if err != nil {
+		return err
+	}
+
+	var failed []string
+	for _, record := range zone.Records {
+		relativeName := strings.TrimSuffix(record.GetName(), zone.GetName())
+		if !testAccRecordName.MatchString(relativeName) {
+			continue
+		}
+
+		log.Printf("[INFO] Deleting record %s %s %s", record.GetName(), record.GetType(), record.GetContent())
+		if _, err := client.RecordsApi.DeleteRecord(ctx, zone.GetId(), record.GetId()).Execute(); err != nil {
+			failed = append(failed, fmt.Sprintf("%s (%s): %v", record.GetName(), record.GetId(), err))
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("unable to delete records: %s", strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
 func TestAccDnsRecord_Validations(t *testing.T) {
 	resource.Test(t, resource.TestCase{
-		PreCheck:          func() { testAccPreCheck(t) },
-		ProviderFactories: testAccProviderFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config:      invalidTtl,
@@ -28,22 +82,26 @@ func TestAccDnsRecord_Validations(t *testing.T) {
 				Config:      invalidPrio,
 				ExpectError: regexp.MustCompile("prio"),
 			},
+			{
+				Config:      invalidPollInterval,
+				ExpectError: regexp.MustCompile("poll_interval to be greater than zero"),
+			},
 		},
 	})
 }
 
 func TestAccDnsRecord_MX(t *testing.T) {
 	resource.Test(t, resource.TestCase{
-		PreCheck:          func() { testAccPreCheck(t) },
-		ProviderFactories: testAccProviderFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: mx,
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "name", "test-acc."+testZoneName),
-					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "type", "MX"),
+					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "type", "mx"),
 					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "content", "a.de"),
-					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "prio", "0"),
+					resource.TestCheckNoResourceAttr("ionosdeveloper_dns_record.r", "prio"),
 					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "ttl", "1000"),
 					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "disabled", "false"),
 				),
@@ -65,30 +123,209 @@ func TestAccDnsRecord_MX(t *testing.T) {
 
 func TestAccDnsRecord_TXT(t *testing.T) {
 	resource.Test(t, resource.TestCase{
-		PreCheck:          func() { testAccPreCheck(t) },
-		ProviderFactories: testAccProviderFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: txt,
-				Check:  resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "content", "\"text\""),
+				Check:  resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "content", "text"),
+			},
+		},
+	})
+}
+
+func TestAccDnsRecord_Import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: mx2,
+			},
+			{
+				ResourceName: "ionosdeveloper_dns_record.r",
+				ImportState:  true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					rs := s.RootModule().Resources["ionosdeveloper_dns_record.r"]
+					return rs.Primary.Attributes["zone_id"] + "/" + rs.Primary.ID, nil
+				},
+				// prio is imported for MX records
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccDnsRecord_Defaults(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: withoutTtl,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "ttl", "3600"),
+					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "disabled", "false"),
+				),
+			},
+			{
+				Config: providerDefaults(600, true) + withoutTtl,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "ttl", "600"),
+					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "disabled", "true"),
+				),
+			},
+			{
+				// The last default is kept if the defaults are removed
+				Config: withoutTtl,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "ttl", "600"),
+					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "disabled", "false"),
+				),
+			},
+			{
+				Config: providerDefaults(600, false) + a,
+				Check:  resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "ttl", "100"),
+			},
+		},
+	})
+}
+
+func TestAccDnsRecord_ZoneName(t *testing.T) {
+	var initialId string
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: withZoneName(testZoneName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("ionosdeveloper_dns_record.r", "zone_id", "data.ionosdeveloper_dns_zone.z", "id"),
+					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "zone_name", testZoneName),
+					getCurrentId("ionosdeveloper_dns_record.r", &initialId),
+				),
+			},
+			{
+				Config:      withZoneName("inexistent-zone.de"),
+				ExpectError: regexp.MustCompile("no DNS zone with the name inexistent-zone.de"),
+			},
+			{
+				// Switching to the inferred zone keeps the record
+				Config: withInferredZone,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					checkSameId("ionosdeveloper_dns_record.r", &initialId),
+					resource.TestCheckResourceAttrPair("ionosdeveloper_dns_record.r", "zone_id", "data.ionosdeveloper_dns_zone.z", "id"),
+					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "zone_name", testZoneName),
+				),
+			},
+			{
+				Config: a,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					checkSameId("ionosdeveloper_dns_record.r", &initialId),
+					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "zone_name", testZoneName),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDnsRecord_RelativeName(t *testing.T) {
+	var initialId string
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: relativeName("test-acc"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "name", "test-acc"),
+					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "fqdn", "test-acc."+testZoneName),
+					getCurrentId("ionosdeveloper_dns_record.r", &initialId),
+				),
+			},
+			{
+				Config: relativeName("test-acc." + testZoneName + "."),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					checkSameId("ionosdeveloper_dns_record.r", &initialId),
+					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "fqdn", "test-acc."+testZoneName),
+				),
+			},
+			{
+				Config: relativeName("test-acc2"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					checkDifferentId("ionosdeveloper_dns_record.r", &initialId),
+					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "fqdn", "test-acc2."+testZoneName),
+				),
+			},
+			{
+				Config:      relativeNameWithoutZone,
+				ExpectError: regexp.MustCompile(`the zone of the relative name "test-acc" must be set`),
 			},
 		},
 	})
 }
 
+func TestUpgradeDnsRecordState(t *testing.T) {
+	state := map[string]interface{}{
+		"zone_id":  "zone",
+		"id":       "record",
+		"name":     "www.example.com",
+		"type":     "A",
+		"content":  "1.1.1.1",
+		"ttl":      float64(3600),
+		"prio":     float64(0),
+		"disabled": false,
+		"wait_for_propagation": []interface{}{map[string]interface{}{
+			"nameservers":   []interface{}{},
+			"timeout":       "5m",
+			"poll_interval": "5s",
+		}},
+	}
+	expected := map[string]interface{}{
+		"zone_id":   "zone",
+		"zone_name": nil,
+		"id":        "record",
+		"name":      "www.example.com",
+		"fqdn":      nil,
+		"type":      "A",
+		"content":   "1.1.1.1",
+		"ttl":       float64(3600),
+		"prio":      nil,
+		"disabled":  false,
+		"wait_for_propagation": map[string]interface{}{
+			"nameservers":   nil,
+			"timeout":       "5m",
+			"poll_interval": "5s",
+		},
+		"on_conflict":         onConflictError,
+		"deletion_protection": false,
+	}
+
+	if upgraded := upgradeDnsRecordState(state); !reflect.DeepEqual(upgraded, expected) {
+		t.Errorf("expected %v, got %v", expected, upgraded)
+	}
+
+	state["prio"] = float64(10)
+	state["wait_for_propagation"] = []interface{}{}
+	upgraded := upgradeDnsRecordState(state)
+	if upgraded["prio"] != float64(10) || upgraded["wait_for_propagation"] != nil {
+		t.Errorf("expected prio 10 without wait_for_propagation, got %v", upgraded)
+	}
+}
+
 func TestAccDnsRecord_UpdateType(t *testing.T) {
 	var initialId string
 	resource.Test(t, resource.TestCase{
-		PreCheck:          func() { testAccPreCheck(t) },
-		ProviderFactories: testAccProviderFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: mx,
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "name", "test-acc."+testZoneName),
-					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "type", "MX"),
+					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "type", "mx"),
 					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "content", "a.de"),
-					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "prio", "0"),
+					resource.TestCheckNoResourceAttr("ionosdeveloper_dns_record.r", "prio"),
 					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "ttl", "1000"),
 					getCurrentId("ionosdeveloper_dns_record.r", &initialId),
 				),
@@ -99,7 +336,7 @@ func TestAccDnsRecord_UpdateType(t *testing.T) {
 					checkDifferentId("ionosdeveloper_dns_record.r", &initialId),
 					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "name", "test-acc."+testZoneName),
 					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "type", "TXT"),
-					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "content", "\"text\""),
+					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "content", "text"),
 					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "ttl", "100"),
 				),
 			},
@@ -110,14 +347,14 @@ func TestAccDnsRecord_UpdateType(t *testing.T) {
 func TestAccDnsRecord_UpdateName(t *testing.T) {
 	var initialId string
 	resource.Test(t, resource.TestCase{
-		PreCheck:          func() { testAccPreCheck(t) },
-		ProviderFactories: testAccProviderFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: mx,
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "name", "test-acc."+testZoneName),
-					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "type", "MX"),
+					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "type", "mx"),
 					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "content", "a.de"),
 					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "ttl", "1000"),
 					getCurrentId("ionosdeveloper_dns_record.r", &initialId),
@@ -128,7 +365,7 @@ func TestAccDnsRecord_UpdateName(t *testing.T) {
 				Check: resource.ComposeAggregateTestCheckFunc(
 					checkDifferentId("ionosdeveloper_dns_record.r", &initialId),
 					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "name", "test-acc2."+testZoneName),
-					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "type", "MX"),
+					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "type", "mx"),
 					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "content", "a.de"),
 					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "ttl", "1000"),
 				),
@@ -140,8 +377,8 @@ func TestAccDnsRecord_UpdateName(t *testing.T) {
 func TestAccDnsRecord_NormalizedName(t *testing.T) {
 	var initialId string
 	resource.Test(t, resource.TestCase{
-		PreCheck:          func() { testAccPreCheck(t) },
-		ProviderFactories: testAccProviderFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: punycodeName,
@@ -154,13 +391,447 @@ func TestAccDnsRecord_NormalizedName(t *testing.T) {
 				Config: unicodeName,
 				Check: resource.ComposeAggregateTestCheckFunc(
 					checkSameId("ionosdeveloper_dns_record.r", &initialId),
-					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "name", "ss.test-acc."+testZoneName),
+					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "name", "ß.test-acc."+testZoneName),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDnsRecord_ApiError(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t); testAccFakeOnly(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				PreConfig:   func() { testFakeDnsApi.FailRequests("CreateRecords", http.StatusInternalServerError, 1) },
+				Config:      a,
+				ExpectError: regexp.MustCompile("Unable to create zone record"),
+			},
+			{
+				Config: a,
+				Check:  resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "content", "1.1.1.1"),
+			},
+		},
+	})
+}
+
+func TestAccDnsRecord_ConflictRetry(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t); testAccFakeOnly(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					testFakeDnsApi.FailRequests("CreateRecords", http.StatusConflict, 1)
+					testFakeDnsApi.FailRequests("CreateRecords", http.StatusTooManyRequests, 1)
+				},
+				Config: onConflict(onConflictError, "1.1.1.1"),
+			},
+			{
+				PreConfig: func() { testFakeDnsApi.FailRequests("UpdateRecord", http.StatusConflict, maxWriteRetries) },
+				Config:    onConflict(onConflictError, "2.2.2.2"),
+				Check:     resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "content", "2.2.2.2"),
+			},
+			{
+				PreConfig:   func() { testFakeDnsApi.FailRequests("UpdateRecord", http.StatusConflict, maxWriteRetries+1) },
+				Config:      onConflict(onConflictError, "3.3.3.3"),
+				ExpectError: regexp.MustCompile("Unable to update record"),
+			},
+		},
+	})
+}
+
+func TestAccDnsRecord_OnConflict(t *testing.T) {
+	var existingId string
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				PreConfig:   func() { existingId = createUnmanagedRecord(t, "1.1.1.1") },
+				Config:      onConflict(onConflictError, "1.1.1.1"),
+				ExpectError: regexp.MustCompile(`(?s)record\s+test-acc\..*exists\s+already,\s+set\s+on_conflict\s+to\s+"adopt"`),
+			},
+			{
+				Config: onConflict(onConflictAdopt, "1.1.1.1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					checkSameId("ionosdeveloper_dns_record.r", &existingId),
+					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "ttl", "1000"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDnsRecord_OnConflictOverwrite(t *testing.T) {
+	var existingId string
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() { existingId = createUnmanagedRecord(t, "1.1.1.1") },
+				Config:    onConflict(onConflictOverwrite, "2.2.2.2"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					checkSameId("ionosdeveloper_dns_record.r", &existingId),
+					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "content", "2.2.2.2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDnsRecord_Ownership(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             checkOwnershipMarker(""),
+		Steps: []resource.TestStep{
+			{
+				Config: ownedRecord("test-acc", onConflictError, "1.1.1.1"),
+				Check:  checkOwnershipMarker("test-acc"),
+			},
+			{
+				Config: ownedRecord("test-acc", onConflictError, "2.2.2.2"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					checkOwnershipMarker("test-acc"),
+					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "content", "2.2.2.2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDnsRecord_OwnershipForeign(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					createTestRecord(t, "_owner-a.test-acc."+testZoneName, dnsSdk.TXT, ownershipMarkerContent("other"))
+					createUnmanagedRecord(t, "1.1.1.1")
+				},
+				Config:      ownedRecord("test-acc", onConflictAdopt, "1.1.1.1"),
+				ExpectError: regexp.MustCompile(`(?s)A\s+records\s+named\s+test-acc\..*are\s+owned\s+by\s+"other"`),
+			},
+			{
+				Config:      ownedRecord("test-acc", onConflictError, "2.2.2.2"),
+				ExpectError: regexp.MustCompile(`(?s)A\s+records\s+named\s+test-acc\..*are\s+owned\s+by\s+"other"`),
+			},
+		},
+	})
+}
+
+func TestAccDnsRecord_OwnershipUnowned(t *testing.T) {
+	var id string
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             checkOwnershipMarker(""),
+		Steps: []resource.TestStep{
+			{
+				Config: ownedRecord("", onConflictError, "1.1.1.1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					getCurrentId("ionosdeveloper_dns_record.r", &id),
+					checkOwnershipMarker(""),
+				),
+			},
+			{
+				// The record in the state is claimed by its first update
+				Config: ownedRecord("test-acc", onConflictError, "2.2.2.2"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					checkSameId("ionosdeveloper_dns_record.r", &id),
+					checkOwnershipMarker("test-acc"),
+					resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "content", "2.2.2.2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDnsRecord_OwnershipUnownedDelete(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             checkOwnershipMarker(""),
+		Steps: []resource.TestStep{
+			{
+				Config: ownedRecord("", onConflictError, "1.1.1.1"),
+			},
+			{
+				// The record in the state is deleted without a marker
+				Config:  ownedRecord("test-acc", onConflictError, "1.1.1.1"),
+				Destroy: true,
+			},
+		},
+	})
+}
+
+func TestAccDnsRecord_OwnershipAdopt(t *testing.T) {
+	var existingId string
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             checkOwnershipMarker(""),
+		Steps: []resource.TestStep{
+			{
+				PreConfig:   func() { existingId = createUnmanagedRecord(t, "1.1.1.1") },
+				Config:      ownedRecord("test-acc", onConflictError, "2.2.2.2"),
+				ExpectError: regexp.MustCompile(`(?s)1\s+A\s+records\s+named\s+test-acc\..*exist\s+without\s+an\s+ownership`),
+			},
+			{
+				Config: ownedRecord("test-acc", onConflictAdopt, "1.1.1.1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					checkSameId("ionosdeveloper_dns_record.r", &existingId),
+					checkOwnershipMarker("test-acc"),
 				),
 			},
 		},
 	})
 }
 
+func TestAccDnsRecord_DeletionProtection(t *testing.T) {
+	var id string
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: protectedRecord("test-acc", true),
+				Check:  getCurrentId("ionosdeveloper_dns_record.r", &id),
+			},
+			{
+				Config:      protectedRecord("test-acc2", true),
+				ExpectError: regexp.MustCompile(`(?s)is\s+protected\s+by\s+deletion_protection\s+and\s+cannot\s+be\s+replaced\s+to\s+change\s+name`),
+			},
+			{
+				// Turning off the protection in the same apply does not allow the replacement
+				Config:      protectedRecord("test-acc2", false),
+				ExpectError: regexp.MustCompile(`(?s)is\s+protected\s+by\s+deletion_protection\s+and\s+cannot\s+be\s+replaced`),
+			},
+			{
+				Config:      zoneConfig(testZoneName),
+				ExpectError: regexp.MustCompile("Record is protected by deletion_protection"),
+			},
+			{
+				Config: protectedRecord("test-acc", false),
+				Check:  checkSameId("ionosdeveloper_dns_record.r", &id),
+			},
+		},
+	})
+}
+
+func TestAccDnsRecord_ReadOnly(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      readOnlyRecord(true, "1.1.1.1"),
+				ExpectError: regexp.MustCompile(`(?s)POST /.*/zones/.*/records\n\[.*"content": "1\.1\.1\.1"`),
+			},
+			{
+				// The record is reported rather than its ownership marker
+				Config:      onConflict(onConflictError, "1.1.1.1") + readOnlyOwnedProvider,
+				ExpectError: regexp.MustCompile(`(?s)Unable to create zone record.*POST /.*/zones/.*/records\n\[.*"content": "1\.1\.1\.1"`),
+			},
+			{
+				Config: readOnlyRecord(false, "1.1.1.1"),
+			},
+			{
+				// Refreshing and planning work as usual
+				Config:             readOnlyRecord(true, "2.2.2.2"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config:      readOnlyRecord(true, "2.2.2.2"),
+				ExpectError: regexp.MustCompile(`(?s)PUT /.*/zones/.*/records/.*"content": "2\.2\.2\.2"`),
+			},
+			{
+				Config:      zoneConfig(testZoneName) + readOnlyProvider,
+				ExpectError: regexp.MustCompile(`DELETE /.*/zones/.*/records/`),
+			},
+			{
+				Config: readOnlyRecord(false, "1.1.1.1"),
+			},
+		},
+	})
+}
+
+func TestAccDnsRecord_PlanConflicts(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      cnameAtApex,
+				ExpectError: regexp.MustCompile(`a CNAME record cannot be created at the apex of the\s+zone`),
+			},
+			{
+				Config:      plannedCnameConflict,
+				ExpectError: regexp.MustCompile(`(?s)a\s+CNAME\s+record\s+cannot\s+coexist\s+with\s+other\s+records\s+with\s+the\s+same\s+name,.*is\s+planned\s+by\s+another\s+resource`),
+			},
+			{
+				Config:      plannedDuplicates,
+				ExpectError: regexp.MustCompile(`(?s)the\s+A\s+record\s+test-acc\..*with\s+the\s+content\s+"1.1.1.1"\s+is\s+planned\s+by\s+another\s+resource`),
+			},
+			{
+				PreConfig:   func() { createUnmanagedRecord(t, "1.1.1.1") },
+				Config:      planChecksProvider(false) + relativeCname("test-acc"),
+				ExpectError: regexp.MustCompile(`(?s)a\s+CNAME\s+record\s+cannot\s+coexist\s+with\s+other\s+records\s+with\s+the\s+same\s+name,.*the\s+A\s+record\s+test-acc\..*exists\s+already`),
+			},
+			{
+				// The conflict is reported by the API instead
+				Config:      planChecksProvider(true) + relativeCname("test-acc"),
+				ExpectError: regexp.MustCompile("Unable to create zone record"),
+			},
+		},
+	})
+}
+
+func TestAccDnsRecord_PlanConflictsResolved(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: planChecksProvider(false) + renamedRecord("test-acc", false),
+			},
+			{
+				// The CNAME record conflicts with the A record in the zone
+				Config:      planChecksProvider(false) + renamedRecord("test-acc", true),
+				ExpectError: regexp.MustCompile(`(?s)a\s+CNAME\s+record\s+cannot\s+coexist\s+with\s+other\s+records\s+with\s+the\s+same\s+name`),
+			},
+			{
+				// The A record is renamed in the same run, before the CNAME record is created
+				Config: planChecksProvider(false) + renamedRecord("test-acc-renamed", true),
+				Check:  resource.TestCheckResourceAttrSet("ionosdeveloper_dns_record.r", "id"),
+			},
+		},
+	})
+}
+
+func TestAccDnsRecord_WaitForPropagation(t *testing.T) {
+	testAccFakeOnly(t)
+	nameserver := startTestNameserver(t, servedFakeRecords)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: waitForPropagationConfig(nameserver.Addr(), "1.1.1.1"),
+				Check: resource.TestCheckFunc(func(s *terraform.State) error {
+					if nameserver.Queries() == 0 {
+						return fmt.Errorf("the nameserver has not been queried")
+					}
+					return nil
+				}),
+			},
+			{
+				Config: waitForPropagationConfig(nameserver.Addr(), "2.2.2.2"),
+				Check:  resource.TestCheckResourceAttr("ionosdeveloper_dns_record.r", "content", "2.2.2.2"),
+			},
+		},
+	})
+}
+
+func TestAccDnsRecord_WaitForPropagationTimeout(t *testing.T) {
+	testAccFakeOnly(t)
+	nameserver := startTestNameserver(t, func() []dns.RR { return nil })
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      waitForPropagationConfig(nameserver.Addr(), "1.1.1.1"),
+				ExpectError: regexp.MustCompile("the A record test-acc\\..* is not served by"),
+			},
+		},
+	})
+}
+
+// servedFakeRecords returns the enabled records of the test zone of the fake DNS API
+func servedFakeRecords() []dns.RR {
+	var served []dns.RR
+	for _, record := range testFakeDnsApi.Records(testFakeDnsApi.ZoneId(testZoneName)) {
+		if record.GetDisabled() {
+			continue
+		}
+		rr, err := dns.NewRR(fmt.Sprintf("%s. %d IN %s %s", record.GetName(), record.GetTtl(), record.GetType(), record.GetContent()))
+		if err == nil {
+			served = append(served, rr)
+		}
+	}
+	return served
+}
+
+// createUnmanagedRecord creates the A record test-acc with a ttl of 3600 outside of Terraform, and deletes it
+// at the end of the test unless a resource adopted it.
+func createUnmanagedRecord(t *testing.T, content string) string {
+	return createTestRecord(t, "test-acc."+testZoneName, dnsSdk.A, content)
+}
+
+// createTestRecord creates a record that is not managed by the tests, and deletes it once the test is done
+func createTestRecord(t *testing.T, name string, recordType dnsSdk.RecordTypes, content string) string {
+	ctx := context.Background()
+	client, err := newDnsApiClientFromEnv(ctx, "terraform-provider-ionosdeveloper/test")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	zone, err := getZoneWithRecords(ctx, client, testZoneName)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	record := dnsSdk.NewRecord()
+	record.SetName(name)
+	record.SetType(recordType)
+	record.SetContent(content)
+	record.SetTtl(3600)
+	created, _, err := client.RecordsApi.CreateRecords(ctx, zone.GetId()).Record([]dnsSdk.Record{*record}).Execute()
+	if err != nil {
+		t.Fatalf("unable to create record: %v\n%s", err, getIndentedBody(err))
+	}
+
+	t.Cleanup(func() {
+		client.RecordsApi.DeleteRecord(ctx, zone.GetId(), created[0].GetId()).Execute()
+	})
+
+	return created[0].GetId()
+}
+
+func TestSweepZoneRecords(t *testing.T) {
+	api := newFakeDnsApi()
+	defer api.Close()
+
+	zoneId := api.AddZone("example.com")
+	for _, name := range []string{"test-acc.example.com", "ss.test-acc.example.com", "_dmarc.test-acc2.example.com", "example.com", "www.example.com", "test-accounting.example.com"} {
+		record := dnsSdk.NewRecord()
+		record.SetName(name)
+		record.SetType(dnsSdk.TXT)
+		record.SetContent(`"test"`)
+		api.AddRecord(zoneId, *record)
+	}
+
+	client := newDnsApiClient(api.URL(), defaultAuthHeader, fakeApiKey, "test", nil)
+	if err := sweepZoneRecords(context.Background(), client, "example.com"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var remaining []string
+	for _, record := range api.Records(zoneId) {
+		remaining = append(remaining, record.GetName())
+	}
+	if expected := "example.com,www.example.com,test-accounting.example.com"; strings.Join(remaining, ",") != expected {
+		t.Errorf("expected the records %s to remain, got %s", expected, strings.Join(remaining, ","))
+	}
+}
+
 func getCurrentId(n string, id *string) resource.TestCheckFunc {
 	return resource.TestCheckFunc(func(s *terraform.State) error {
 		// find the corresponding state object
@@ -247,6 +918,224 @@ resource ionosdeveloper_dns_record r {
   ttl      = 100
 }`
 
+var withoutTtl = zoneConfig(testZoneName) + `
+resource ionosdeveloper_dns_record r {
+  zone_id  = data.ionosdeveloper_dns_zone.z.id
+  name     = "test-acc.${data.ionosdeveloper_dns_zone.z.name}"
+  type     = "A"
+  content  = "1.1.1.1"
+}`
+
+func withZoneName(zoneName string) string {
+	return zoneConfig(testZoneName) + fmt.Sprintf(`
+resource ionosdeveloper_dns_record r {
+  zone_name = %q
+  name      = "test-acc.${data.ionosdeveloper_dns_zone.z.name}"
+  type      = "A"
+  content   = "1.1.1.1"
+  ttl       = 100
+}`, zoneName)
+}
+
+var withInferredZone = zoneConfig(testZoneName) + `
+resource ionosdeveloper_dns_record r {
+  name    = "test-acc.${data.ionosdeveloper_dns_zone.z.name}"
+  type    = "A"
+  content = "1.1.1.1"
+  ttl     = 100
+}`
+
+func relativeName(name string) string {
+	return fmt.Sprintf(`
+resource ionosdeveloper_dns_record r {
+  zone_name = %q
+  name      = %q
+  type      = "A"
+  content   = "1.1.1.1"
+  ttl       = 100
+}`, testZoneName, name)
+}
+
+var relativeNameWithoutZone = `
+resource ionosdeveloper_dns_record r {
+  name    = "test-acc"
+  type    = "A"
+  content = "1.1.1.1"
+  ttl     = 100
+}`
+
+func onConflict(mode string, content string) string {
+	return fmt.Sprintf(`
+resource ionosdeveloper_dns_record r {
+  zone_name   = %q
+  name        = "test-acc"
+  type        = "A"
+  content     = %q
+  ttl         = 1000
+  on_conflict = %q
+}`, testZoneName, content, mode)
+}
+
+func protectedRecord(name string, protected bool) string {
+	return fmt.Sprintf(`
+resource ionosdeveloper_dns_record r {
+  zone_name           = %q
+  name                = %q
+  type                = "A"
+  content             = "1.1.1.1"
+  deletion_protection = %t
+}`, testZoneName, name, protected)
+}
+
+var readOnlyProvider = `
+provider ionosdeveloper {
+  read_only = true
+}`
+
+var readOnlyOwnedProvider = `
+provider ionosdeveloper {
+  read_only = true
+  owner_id  = "test"
+}`
+
+func readOnlyRecord(readOnly bool, content string) string {
+	config := onConflict(onConflictError, content)
+	if readOnly {
+		config += readOnlyProvider
+	}
+	return config
+}
+
+// ownedRecord configures the provider with the owner_id, unless it is empty
+func ownedRecord(ownerId string, mode string, content string) string {
+	if ownerId == "" {
+		return onConflict(mode, content)
+	}
+
+	return fmt.Sprintf(`
+provider ionosdeveloper {
+  owner_id = %q
+}
+`, ownerId) + onConflict(mode, content)
+}
+
+// checkOwnershipMarker checks the owner of the A records named test-acc, or that they have no marker if
+// ownerId is empty
+func checkOwnershipMarker(ownerId string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		ctx := context.Background()
+		client, err := newDnsApiClientFromEnv(ctx, "terraform-provider-ionosdeveloper/test")
+		if err != nil {
+			return err
+		}
+		zone, err := getZoneWithRecords(ctx, client, testZoneName)
+		if err != nil {
+			return err
+		}
+
+		var owners []string
+		for _, record := range zone.GetRecords() {
+			if owner, ok := markerOwner(record); ok && strings.EqualFold(record.GetName(), "_owner-a.test-acc."+testZoneName) {
+				owners = append(owners, owner)
+			}
+		}
+
+		switch {
+		case ownerId == "" && len(owners) > 0:
+			return fmt.Errorf("expected no ownership marker, got the markers of %v", owners)
+		case ownerId != "" && (len(owners) != 1 || owners[0] != ownerId):
+			return fmt.Errorf("expected the ownership marker of %s, got the markers of %v", ownerId, owners)
+		}
+		return nil
+	}
+}
+
+func relativeCname(name string) string {
+	return fmt.Sprintf(`
+resource ionosdeveloper_dns_record r {
+  zone_name = %q
+  name      = %q
+  type      = "CNAME"
+  content   = "example.com"
+  ttl       = 100
+}`, testZoneName, name)
+}
+
+var cnameAtApex = relativeCname("@")
+
+var plannedCnameConflict = relativeCname("test-acc") + fmt.Sprintf(`
+resource ionosdeveloper_dns_record r2 {
+  zone_name = %q
+  name      = "test-acc"
+  type      = "TXT"
+  content   = "text"
+  ttl       = 100
+}`, testZoneName)
+
+// renamedRecord returns an A record with the given name and, if cname is set, a CNAME record test-acc that
+// depends on it
+func renamedRecord(name string, cname bool) string {
+	config := fmt.Sprintf(`
+resource ionosdeveloper_dns_record a {
+  zone_name = %q
+  name      = %q
+  type      = "A"
+  content   = "1.1.1.1"
+  ttl       = 100
+}
+`, testZoneName, name)
+	if cname {
+		config += fmt.Sprintf(`
+resource ionosdeveloper_dns_record r {
+  zone_name  = %q
+  name       = "test-acc"
+  type       = "CNAME"
+  content    = "example.com"
+  ttl        = 100
+  depends_on = [ionosdeveloper_dns_record.a]
+}`, testZoneName)
+	}
+	return config
+}
+
+var plannedDuplicates = fmt.Sprintf(`
+resource ionosdeveloper_dns_record r {
+  count     = 2
+  zone_name = %q
+  name      = "test-acc"
+  type      = "A"
+  content   = "1.1.1.1"
+  ttl       = 100
+}`, testZoneName)
+
+func waitForPropagationConfig(nameserver string, content string) string {
+	return fmt.Sprintf(`
+resource ionosdeveloper_dns_record r {
+  zone_name = %q
+  name      = "test-acc"
+  type      = "A"
+  content   = %q
+  ttl       = 100
+
+  wait_for_propagation = {
+    nameservers   = [%q]
+    timeout       = "2s"
+    poll_interval = "100ms"
+  }
+}`, testZoneName, content, nameserver)
+}
+
+func providerDefaults(ttl int, disabled bool) string {
+	return fmt.Sprintf(`
+provider ionosdeveloper {
+  defaults {
+    ttl      = %d
+    disabled = %t
+  }
+}
+`, ttl, disabled)
+}
+
 var a = zoneConfig(testZoneName) + `
 resource ionosdeveloper_dns_record r {
   zone_id  = data.ionosdeveloper_dns_zone.z.id
@@ -284,6 +1173,19 @@ resource ionosdeveloper_dns_record r {
   prio     = 65536
 }`
 
+var invalidPollInterval = zoneConfig(testZoneName) + `
+resource ionosdeveloper_dns_record r {
+  zone_id  = data.ionosdeveloper_dns_zone.z.id
+  name     = "test-acc.${data.ionosdeveloper_dns_zone.z.name}"
+  type     = "A"
+  content  = "1.1.1.1"
+  ttl      = 1000
+
+  wait_for_propagation = {
+    poll_interval = "0s"
+  }
+}`
+
 var punycodeName = zoneConfig(testZoneName) + `
 resource ionosdeveloper_dns_record r {
   zone_id  = data.ionosdeveloper_dns_zone.z.id
@@ -301,3 +1203,11 @@ resource ionosdeveloper_dns_record r {
   content  = "1.1.1.1"
   ttl      = 1000
 }`
+
+func planChecksProvider(skip bool) string {
+	return fmt.Sprintf(`
+provider ionosdeveloper {
+  skip_plan_checks = %t
+}
+`, skip)
+}
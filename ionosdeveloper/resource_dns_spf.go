@@ -0,0 +1,208 @@
+package ionosdeveloper
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	// RFC 7208 section 4.6.4 limits the mechanisms that cause DNS lookups during evaluation
+	spfMaxLookups = 10
+	// RFC 7208 section 3.4 recommends keeping the record small enough for a 512 byte DNS response
+	spfMaxLength = 450
+)
+
+var spfAllQualifiers = map[string]string{
+	"pass":     "+all",
+	"fail":     "-all",
+	"softfail": "~all",
+	"neutral":  "?all",
+}
+
+func resourceDnsSpf() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceDnsSpfCreate,
+		ReadContext:   resourceTxtRecordRead,
+		UpdateContext: resourceTxtRecordUpdate,
+		DeleteContext: resourceTxtRecordDelete,
+		CustomizeDiff: resourceDnsSpfCustomizeDiff,
+		Schema: map[string]*schema.Schema{
+			"zone_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+			},
+			"id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return normalizeDomainName(old) == normalizeDomainName(new)
+				},
+			},
+			"include": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.NoZeroValues,
+				},
+			},
+			"ip4": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateSpfNetwork(false),
+				},
+			},
+			"ip6": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateSpfNetwork(true),
+				},
+			},
+			"a": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.NoZeroValues,
+				},
+			},
+			"mx": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.NoZeroValues,
+				},
+			},
+			"all": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "softfail",
+				ValidateFunc: validation.StringInSlice([]string{"pass", "fail", "softfail", "neutral"}, false),
+			},
+			"ttl": {
+				Type:             schema.TypeInt,
+				Required:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(60)),
+			},
+			"disabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"content": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceDnsSpfCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return resourceTxtRecordCreate(ctx, d, m, d.Get("name").(string))
+}
+
+// resourceDnsSpfCustomizeDiff renders the policy at plan time, so that the resulting content is shown in the
+// plan and policies exceeding the lookup or length limits are rejected before anything is applied.
+func resourceDnsSpfCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	for _, key := range []string{"include", "ip4", "ip6", "a", "mx", "all"} {
+		if !d.NewValueKnown(key) {
+			return d.SetNewComputed("content")
+		}
+	}
+
+	if lookups := spfLookupCount(d); lookups > spfMaxLookups {
+		return fmt.Errorf("the SPF policy requires %d DNS lookups, at most %d are allowed", lookups, spfMaxLookups)
+	}
+
+	content := renderSpf(d)
+	if len(content) > spfMaxLength {
+		return fmt.Errorf("the SPF policy is %d characters long, at most %d are allowed", len(content), spfMaxLength)
+	}
+
+	if content != d.Get("content").(string) {
+		return d.SetNew("content", content)
+	}
+
+	return nil
+}
+
+func renderSpf(d attributeGetter) string {
+	terms := []string{"v=spf1"}
+
+	for _, ip := range d.Get("ip4").([]interface{}) {
+		terms = append(terms, "ip4:"+ip.(string))
+	}
+	for _, ip := range d.Get("ip6").([]interface{}) {
+		terms = append(terms, "ip6:"+ip.(string))
+	}
+	for _, domain := range d.Get("a").([]interface{}) {
+		terms = append(terms, spfDomainMechanism("a", domain.(string)))
+	}
+	for _, domain := range d.Get("mx").([]interface{}) {
+		terms = append(terms, spfDomainMechanism("mx", domain.(string)))
+	}
+	for _, domain := range d.Get("include").([]interface{}) {
+		terms = append(terms, "include:"+domain.(string))
+	}
+
+	terms = append(terms, spfAllQualifiers[d.Get("all").(string)])
+
+	return strings.Join(terms, " ")
+}
+
+func spfLookupCount(d attributeGetter) int {
+	return len(d.Get("include").([]interface{})) + len(d.Get("a").([]interface{})) + len(d.Get("mx").([]interface{}))
+}
+
+// spfDomainMechanism renders an a or mx mechanism. The domain "@" refers to the domain of the record itself,
+// optionally followed by a prefix length, e.g. "@/24".
+func spfDomainMechanism(mechanism string, domain string) string {
+	if strings.HasPrefix(domain, "@") {
+		return mechanism + strings.TrimPrefix(domain, "@")
+	}
+	return mechanism + ":" + domain
+}
+
+func validateSpfNetwork(ipv6 bool) schema.SchemaValidateFunc {
+	return func(i interface{}, k string) ([]string, []error) {
+		value, ok := i.(string)
+		if !ok {
+			return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+		}
+
+		ip := net.ParseIP(value)
+		if ip == nil {
+			var err error
+			if ip, _, err = net.ParseCIDR(value); err != nil {
+				return nil, []error{fmt.Errorf("expected %s to contain a valid IP address or network, got: %s", k, value)}
+			}
+		}
+
+		if (ip.To4() == nil) != ipv6 {
+			if ipv6 {
+				return nil, []error{fmt.Errorf("expected %s to contain an IPv6 address or network, got: %s", k, value)}
+			}
+			return nil, []error{fmt.Errorf("expected %s to contain an IPv4 address or network, got: %s", k, value)}
+		}
+
+		return nil, nil
+	}
+}
@@ -0,0 +1,117 @@
+//go:build all || dns
+
+package ionosdeveloper
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDnsSpf(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: spf,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ionosdeveloper_dns_spf.s", "name", "test-acc."+testZoneName),
+					resource.TestCheckResourceAttr("ionosdeveloper_dns_spf.s", "content", "v=spf1 ip4:192.0.2.0/24 mx include:_spf.example.com ~all"),
+				),
+			},
+			{
+				Config: spfUpdated,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ionosdeveloper_dns_spf.s", "content", "v=spf1 ip6:2001:db8::/32 a:mail.example.com -all"),
+					resource.TestCheckResourceAttr("ionosdeveloper_dns_spf.s", "ttl", "600"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDnsSpf_Validations(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      spfTooManyLookups,
+				ExpectError: regexp.MustCompile("requires 11 DNS lookups"),
+			},
+			{
+				Config:      spfInvalidIp4,
+				ExpectError: regexp.MustCompile("IPv4"),
+			},
+		},
+	})
+}
+
+type testAttributeGetter map[string]interface{}
+
+func (g testAttributeGetter) Get(key string) interface{} {
+	if value, ok := g[key]; ok {
+		return value
+	}
+	if key == "all" {
+		return "softfail"
+	}
+	return []interface{}{}
+}
+
+func TestRenderSpf(t *testing.T) {
+	d := testAttributeGetter{
+		"ip4":     []interface{}{"192.0.2.1", "198.51.100.0/24"},
+		"a":       []interface{}{"@", "@/24", "web.example.com"},
+		"mx":      []interface{}{"@"},
+		"include": []interface{}{"_spf.example.com"},
+		"all":     "fail",
+	}
+
+	expected := "v=spf1 ip4:192.0.2.1 ip4:198.51.100.0/24 a a/24 a:web.example.com mx include:_spf.example.com -all"
+	if actual := renderSpf(d); actual != expected {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+
+	if lookups := spfLookupCount(d); lookups != 5 {
+		t.Errorf("expected 5 lookups, got %d", lookups)
+	}
+}
+
+var spf = zoneConfig(testZoneName) + `
+resource ionosdeveloper_dns_spf s {
+  zone_id = data.ionosdeveloper_dns_zone.z.id
+  name    = "test-acc.${data.ionosdeveloper_dns_zone.z.name}"
+  ip4     = ["192.0.2.0/24"]
+  mx      = ["@"]
+  include = ["_spf.example.com"]
+  ttl     = 3600
+}`
+
+var spfUpdated = zoneConfig(testZoneName) + `
+resource ionosdeveloper_dns_spf s {
+  zone_id = data.ionosdeveloper_dns_zone.z.id
+  name    = "test-acc.${data.ionosdeveloper_dns_zone.z.name}"
+  ip6     = ["2001:db8::/32"]
+  a       = ["mail.example.com"]
+  all     = "fail"
+  ttl     = 600
+}`
+
+var spfTooManyLookups = zoneConfig(testZoneName) + `
+resource ionosdeveloper_dns_spf s {
+  zone_id = data.ionosdeveloper_dns_zone.z.id
+  name    = "test-acc.${data.ionosdeveloper_dns_zone.z.name}"
+  include = [for i in range(11) : "_spf${i}.example.com"]
+  ttl     = 3600
+}`
+
+var spfInvalidIp4 = zoneConfig(testZoneName) + `
+resource ionosdeveloper_dns_spf s {
+  zone_id = data.ionosdeveloper_dns_zone.z.id
+  name    = "test-acc.${data.ionosdeveloper_dns_zone.z.name}"
+  ip4     = ["2001:db8::1"]
+  ttl     = 3600
+}`
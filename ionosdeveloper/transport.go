@@ -0,0 +1,98 @@
+package ionosdeveloper
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// transportSettings configure the HTTP client used to connect to the DNS API
+type transportSettings struct {
+	HTTPProxy          string
+	CACertFile         string
+	CACertPEM          string
+	InsecureSkipVerify bool
+	ClientCertFile     string
+	ClientKeyFile      string
+	RequestTimeout     time.Duration
+}
+
+func transportSettingsFromResourceData(d attributeGetter) (transportSettings, error) {
+	settings := transportSettings{
+		HTTPProxy:          d.Get("http_proxy").(string),
+		CACertFile:         d.Get("ca_cert_file").(string),
+		CACertPEM:          d.Get("ca_cert_pem").(string),
+		InsecureSkipVerify: d.Get("insecure_skip_verify").(bool),
+		ClientCertFile:     d.Get("client_cert_file").(string),
+		ClientKeyFile:      d.Get("client_key_file").(string),
+	}
+
+	if timeout := d.Get("request_timeout").(string); timeout != "" {
+		var err error
+		if settings.RequestTimeout, err = time.ParseDuration(timeout); err != nil {
+			return settings, fmt.Errorf("invalid request_timeout: %v", err)
+		}
+	}
+
+	return settings, nil
+}
+
+// newHTTPClient creates a client based on the defaults of the net/http package. Without a configured proxy,
+// the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables are used.
+func newHTTPClient(settings transportSettings) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if settings.HTTPProxy != "" {
+		proxyUrl, err := url.Parse(settings.HTTPProxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid http_proxy: %v", err)
+		}
+		transport.Proxy = http.ProxyURL(proxyUrl)
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// Only meant for tests against servers with self-signed certificates
+		InsecureSkipVerify: settings.InsecureSkipVerify, // #nosec G402
+	}
+
+	if settings.CACertFile != "" || settings.CACertPEM != "" {
+		caCertPEM := []byte(settings.CACertPEM)
+		if settings.CACertFile != "" {
+			var err error
+			if caCertPEM, err = os.ReadFile(settings.CACertFile); err != nil {
+				return nil, fmt.Errorf("unable to read ca_cert_file: %v", err)
+			}
+		}
+
+		// The CA bundle is added to the system roots, so that a proxy with a private CA can be used without
+		// breaking connections that are not intercepted
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil || rootCAs == nil {
+			rootCAs = x509.NewCertPool()
+		}
+		if !rootCAs.AppendCertsFromPEM(caCertPEM) {
+			return nil, fmt.Errorf("the CA bundle does not contain any PEM encoded certificate")
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+
+	if settings.ClientCertFile != "" {
+		certificate, err := tls.LoadX509KeyPair(settings.ClientCertFile, settings.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load the client certificate: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	transport.TLSClientConfig = tlsConfig
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   settings.RequestTimeout,
+	}, nil
+}
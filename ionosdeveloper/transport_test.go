@@ -0,0 +1,196 @@
+package ionosdeveloper
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewHTTPClient_CACert(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	caCertPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+	caCertFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caCertFile, []byte(caCertPEM), 0600); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	cases := []struct {
+		name     string
+		settings transportSettings
+		expected string
+	}{
+		{
+			name:     "unknown CA",
+			expected: "certificate",
+		},
+		{
+			name:     "CA bundle",
+			settings: transportSettings{CACertPEM: caCertPEM},
+		},
+		{
+			name:     "CA bundle file",
+			settings: transportSettings{CACertFile: caCertFile},
+		},
+		{
+			name:     "insecure skip verify",
+			settings: transportSettings{InsecureSkipVerify: true},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client, err := newHTTPClient(c.settings)
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+
+			_, err = client.Get(server.URL)
+			if c.expected == "" && err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+			if c.expected != "" && (err == nil || !strings.Contains(err.Error(), c.expected)) {
+				t.Errorf("expected an error containing %q, got %v", c.expected, err)
+			}
+		})
+	}
+}
+
+func TestNewHTTPClient_ClientCert(t *testing.T) {
+	var peerCertificates int
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		peerCertificates = len(r.TLS.PeerCertificates)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	certFile, keyFile := writeTestClientCertificate(t)
+	client, err := newHTTPClient(transportSettings{InsecureSkipVerify: true, ClientCertFile: certFile, ClientKeyFile: keyFile})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if _, err := client.Get(server.URL); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if peerCertificates != 1 {
+		t.Errorf("expected the client certificate to be sent, got %d certificates", peerCertificates)
+	}
+}
+
+func TestNewHTTPClient_Proxy(t *testing.T) {
+	var proxiedUrl string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedUrl = r.URL.String()
+	}))
+	defer proxy.Close()
+
+	client, err := newHTTPClient(transportSettings{HTTPProxy: proxy.URL})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if _, err := client.Get("http://dns.example.com/v1/zones"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if proxiedUrl != "http://dns.example.com/v1/zones" {
+		t.Errorf("expected the request to be sent through the proxy, got %q", proxiedUrl)
+	}
+}
+
+func TestNewHTTPClient_RequestTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	client, err := newHTTPClient(transportSettings{RequestTimeout: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if _, err := client.Get(server.URL); err == nil || !strings.Contains(err.Error(), "Client.Timeout") {
+		t.Errorf("expected a timeout, got %v", err)
+	}
+}
+
+func TestNewHTTPClient_Errors(t *testing.T) {
+	cases := []struct {
+		name     string
+		settings transportSettings
+		expected string
+	}{
+		{
+			name:     "invalid CA bundle",
+			settings: transportSettings{CACertPEM: "not a certificate"},
+			expected: "does not contain any PEM encoded certificate",
+		},
+		{
+			name:     "missing CA bundle file",
+			settings: transportSettings{CACertFile: filepath.Join(t.TempDir(), "missing.pem")},
+			expected: "unable to read ca_cert_file",
+		},
+		{
+			name:     "missing client certificate",
+			settings: transportSettings{ClientCertFile: filepath.Join(t.TempDir(), "missing.pem")},
+			expected: "unable to load the client certificate",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := newHTTPClient(c.settings)
+			if err == nil || !strings.Contains(err.Error(), c.expected) {
+				t.Errorf("expected an error containing %q, got %v", c.expected, err)
+			}
+		})
+	}
+}
+
+func writeTestClientCertificate(t *testing.T) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "terraform-provider-ionosdeveloper"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "client.pem"), filepath.Join(dir, "client-key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	return certFile, keyFile
+}
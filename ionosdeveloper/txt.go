@@ -0,0 +1,112 @@
+package ionosdeveloper
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	dnsSdk "github.com/ionos-developer/dns-sdk-go"
+)
+
+// A single character string of a TXT record is limited to 255 bytes, longer values are split into several
+// character strings that resolvers concatenate.
+const txtCharacterStringLength = 255
+
+// attributeGetter is implemented by both schema.ResourceData and schema.ResourceDiff, so that policies can be
+// rendered at plan time as well as during apply.
+type attributeGetter interface {
+	Get(key string) interface{}
+}
+
+// The functions below implement the convenience resources that render a policy into the content of a single
+// TXT record, like ionosdeveloper_dns_spf. The rendered, unquoted policy is kept in the computed content
+// attribute, which is set by the CustomizeDiff function of each resource.
+
+func resourceTxtRecordCreate(ctx context.Context, d *schema.ResourceData, m interface{}, name string) diag.Diagnostics {
+	record := dnsSdk.NewRecord()
+
+	record.SetName(name)
+	record.SetType(dnsSdk.TXT)
+	record.SetContent(quoteTxtContent(d.Get("content").(string)))
+	record.SetTtl(int32(d.Get("ttl").(int)))
+	record.SetDisabled(d.Get("disabled").(bool))
+
+	createdRecord, diags := createDnsRecord(ctx, m, d.Get("zone_id").(string), record)
+	if diags.HasError() {
+		return diags
+	}
+	d.SetId(createdRecord.GetId())
+	setTxtRecordState(d, createdRecord)
+
+	return diags
+}
+
+func resourceTxtRecordRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	record, diags := readDnsRecord(ctx, m, d.Get("zone_id").(string), d.Id())
+	if diags.HasError() {
+		return diags
+	}
+	setTxtRecordState(d, record)
+
+	return diags
+}
+
+func setTxtRecordState(d *schema.ResourceData, record *dnsSdk.RecordResponse) {
+	d.Set("name", *record.Name)
+	d.Set("content", unquoteTxtContent(*record.Content))
+	d.Set("ttl", *record.Ttl)
+	d.Set("disabled", *record.Disabled)
+}
+
+func resourceTxtRecordUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	recordUpdate := *dnsSdk.NewRecordUpdate()
+
+	if d.HasChange("content") {
+		recordUpdate.SetContent(quoteTxtContent(d.Get("content").(string)))
+	}
+
+	if d.HasChange("ttl") {
+		recordUpdate.SetTtl(int32(d.Get("ttl").(int)))
+	}
+
+	if d.HasChange("disabled") {
+		recordUpdate.SetDisabled(d.Get("disabled").(bool))
+	}
+
+	record, diags := updateDnsRecord(ctx, m, d.Get("zone_id").(string), d.Id(), recordUpdate)
+	if diags.HasError() {
+		return diags
+	}
+	d.SetId(record.GetId())
+	setTxtRecordState(d, record)
+
+	return diags
+}
+
+func resourceTxtRecordDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return deleteDnsRecord(ctx, m, d.Get("zone_id").(string), d.Id())
+}
+
+// quoteTxtContent renders a value as TXT record content, split into quoted character strings.
+func quoteTxtContent(value string) string {
+	var parts []string
+	for len(value) > txtCharacterStringLength {
+		parts = append(parts, `"`+value[:txtCharacterStringLength]+`"`)
+		value = value[txtCharacterStringLength:]
+	}
+	parts = append(parts, `"`+value+`"`)
+
+	return strings.Join(parts, " ")
+}
+
+// unquoteTxtContent joins the character strings of a TXT record content, e.g. "a" "b" becomes ab.
+func unquoteTxtContent(content string) string {
+	content = strings.TrimSpace(content)
+	if !strings.HasPrefix(content, `"`) || !strings.HasSuffix(content, `"`) || len(content) < 2 {
+		return content
+	}
+
+	return strings.Join(strings.Split(content[1:len(content)-1], `" "`), "")
+}
@@ -0,0 +1,144 @@
+package ionosdeveloper
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Values of the write_lock provider argument
+const (
+	writeLockZone   = "zone"
+	writeLockGlobal = "global"
+	writeLockNone   = "none"
+)
+
+const (
+	maxWriteRetries       = 3
+	defaultWriteRetryWait = 250 * time.Millisecond
+
+	// duplicateRecordCode is reported with 409 Conflict as well, but retrying does not resolve it
+	duplicateRecordCode = "DUPLICATE_RECORD"
+)
+
+// zoneRecordsPath matches the paths of the requests changing the records of a zone
+var zoneRecordsPath = regexp.MustCompile(`/v1/zones/([^/]+)/records`)
+
+// writeTransport orders the requests changing the records of a zone, so that Terraform's parallelism does not
+// cause conflicting changes of the same zone, while the changes of different zones are still sent in
+// parallel. Requests failing with 409 Conflict or 429 Too Many Requests are retried with a backoff while the
+// zone stays locked.
+type writeTransport struct {
+	transport   http.RoundTripper
+	granularity string
+	retryWait   time.Duration
+
+	locks keyedMutex
+}
+
+// newWriteTransport locks a mutex per zone_id, a single mutex for all zones with writeLockGlobal, or none
+// with writeLockNone
+func newWriteTransport(transport http.RoundTripper, granularity string) *writeTransport {
+	return &writeTransport{transport: transport, granularity: granularity, retryWait: defaultWriteRetryWait}
+}
+
+func (t *writeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if isReadRequest(req) {
+		return t.transport.RoundTrip(req)
+	}
+
+	if key, ok := t.lockKey(req); ok {
+		unlock := t.locks.lock(key)
+		defer unlock()
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.transport.RoundTrip(req)
+		if err != nil || attempt == maxWriteRetries || !isRetryableWrite(resp) {
+			return resp, err
+		}
+
+		// The body has been consumed by the previous attempt
+		retry := req.Clone(req.Context())
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return resp, nil
+			}
+			if retry.Body, err = req.GetBody(); err != nil {
+				return resp, nil
+			}
+		}
+
+		wait := t.retryWait << attempt
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			wait = time.Duration(seconds) * time.Second
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(wait):
+		}
+		req = retry
+	}
+}
+
+// lockKey returns the key of the mutex of a request, or false if the request is not locked
+func (t *writeTransport) lockKey(req *http.Request) (string, bool) {
+	switch t.granularity {
+	case writeLockNone:
+		return "", false
+	case writeLockGlobal:
+		return "", true
+	}
+
+	match := zoneRecordsPath.FindStringSubmatch(req.URL.Path)
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+// isRetryableWrite reports whether a request failed because of a concurrent change or a rate limit. The body of
+// the response stays readable.
+func isRetryableWrite(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusConflict:
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		return err == nil && !bytes.Contains(body, []byte(duplicateRecordCode))
+	}
+	return false
+}
+
+// keyedMutex provides a mutex per key. The zero value is ready to use.
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+// lock locks the mutex of the key and returns the function unlocking it
+func (k *keyedMutex) lock(key string) func() {
+	k.mu.Lock()
+	if k.locks == nil {
+		k.locks = map[string]*sync.Mutex{}
+	}
+	lock, ok := k.locks[key]
+	if !ok {
+		lock = &sync.Mutex{}
+		k.locks[key] = lock
+	}
+	k.mu.Unlock()
+
+	lock.Lock()
+	return lock.Unlock
+}
@@ -0,0 +1,157 @@
+package ionosdeveloper
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	dnsSdk "github.com/ionos-developer/dns-sdk-go"
+)
+
+func TestWriteTransport_Retry(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		bodies = append(bodies, string(body))
+
+		switch {
+		case strings.Contains(string(body), "duplicate"):
+			w.WriteHeader(http.StatusConflict)
+			fmt.Fprint(w, `[{"code":"DUPLICATE_RECORD"}]`)
+		case len(bodies) == 1:
+			w.WriteHeader(http.StatusTooManyRequests)
+		case len(bodies) == 2:
+			w.WriteHeader(http.StatusConflict)
+			fmt.Fprint(w, `[{"code":"CONFLICT"}]`)
+		default:
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer server.Close()
+
+	transport := newWriteTransport(http.DefaultTransport, writeLockZone)
+	transport.retryWait = time.Millisecond
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Post(server.URL+"/v1/zones/z/records", "application/json", strings.NewReader(`[{"name":"a"}]`))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("expected the request to succeed, got %d", resp.StatusCode)
+	}
+	if len(bodies) != 3 || bodies[2] != `[{"name":"a"}]` {
+		t.Errorf("expected the request to be sent 3 times, got %q", bodies)
+	}
+
+	// Duplicate records are not retried, and the body of the error is kept
+	resp, err = client.Post(server.URL+"/v1/zones/z/records", "application/json", strings.NewReader(`[{"name":"duplicate"}]`))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if len(bodies) != 4 || !strings.Contains(string(body), duplicateRecordCode) {
+		t.Errorf("expected the duplicate record to be reported after 1 request, got %d requests and %s", len(bodies)-3, body)
+	}
+}
+
+func TestWriteTransport_RateLimit(t *testing.T) {
+	api := newFakeDnsApi()
+	defer api.Close()
+
+	zoneId := api.AddZone("example.com")
+	api.SetRateLimit(2)
+
+	transport := newWriteTransport(http.DefaultTransport, writeLockZone)
+	transport.retryWait = 500 * time.Millisecond
+	client := newDnsApiClient(api.URL(), defaultAuthHeader, fakeApiKey, "test", &http.Client{Transport: transport})
+
+	// The third record exceeds the limit and is retried once the window of the rate limit has passed
+	for i := 0; i < 3; i++ {
+		record := testBatchRecord(fmt.Sprintf("r%d.example.com", i), 3600)
+		if _, _, err := client.RecordsApi.CreateRecords(context.Background(), zoneId).Record([]dnsSdk.Record{record}).Execute(); err != nil {
+			t.Fatalf("unexpected error for record %d: %s", i, err)
+		}
+	}
+
+	if records := api.Records(zoneId); len(records) != 3 {
+		t.Errorf("expected 3 records, got %d", len(records))
+	}
+	if calls := api.Calls("CreateRecords"); calls <= 3 {
+		t.Errorf("expected the rate limited request to be retried, got %d requests", calls)
+	}
+}
+
+func TestWriteTransport_Lock(t *testing.T) {
+	cases := []struct {
+		granularity   string
+		serialized    bool
+		parallelZones bool
+	}{
+		{writeLockZone, true, true},
+		{writeLockGlobal, true, false},
+		{writeLockNone, false, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.granularity, func(t *testing.T) {
+			var mu sync.Mutex
+			inFlight := map[string]int{}
+			maxPerZone, maxTotal, total := 0, 0, 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				zone := zoneRecordsPath.FindStringSubmatch(r.URL.Path)[1]
+				mu.Lock()
+				inFlight[zone]++
+				total++
+				if inFlight[zone] > maxPerZone {
+					maxPerZone = inFlight[zone]
+				}
+				if total > maxTotal {
+					maxTotal = total
+				}
+				mu.Unlock()
+
+				time.Sleep(50 * time.Millisecond)
+
+				mu.Lock()
+				inFlight[zone]--
+				total--
+				mu.Unlock()
+			}))
+			defer server.Close()
+
+			client := &http.Client{Transport: newWriteTransport(http.DefaultTransport, c.granularity)}
+
+			var wg sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func(zone string) {
+					defer wg.Done()
+					req, _ := http.NewRequest(http.MethodDelete, server.URL+"/v1/zones/"+zone+"/records/r", nil)
+					if resp, err := client.Do(req); err == nil {
+						resp.Body.Close()
+					}
+				}([]string{"a", "b"}[i%2])
+			}
+			wg.Wait()
+
+			if serialized := maxPerZone == 1; serialized != c.serialized {
+				t.Errorf("expected the requests of a zone to be serialized: %t, got up to %d parallel requests", c.serialized, maxPerZone)
+			}
+			if parallelZones := maxTotal > maxPerZone; parallelZones != c.parallelZones {
+				t.Errorf("expected the zones to be written in parallel: %t, got up to %d parallel requests", c.parallelZones, maxTotal)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package ionosdeveloper
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	dnsSdk "github.com/ionos-developer/dns-sdk-go"
+)
+
+// zoneCache holds the zones of the account for the lifetime of a configured provider, so that resolving
+// zone names does not cost a GetZones request per resource. The list is reloaded once if a zone is not
+// found, in case it has been created since.
+type zoneCache struct {
+	client *dnsSdk.APIClient
+
+	mu     sync.Mutex
+	zones  []dnsSdk.Zone
+	loaded bool
+}
+
+func newZoneCache(client *dnsSdk.APIClient) *zoneCache {
+	return &zoneCache{client: client}
+}
+
+// find returns the first zone accepted by match
+func (c *zoneCache) find(ctx context.Context, match func(zones []dnsSdk.Zone) (dnsSdk.Zone, bool)) (dnsSdk.Zone, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.loaded {
+		if zone, ok := match(c.zones); ok {
+			return zone, true, nil
+		}
+	}
+
+	zones, _, err := c.client.ZonesApi.GetZones(ctx).Execute()
+	if err != nil {
+		return dnsSdk.Zone{}, false, err
+	}
+	c.zones, c.loaded = zones, true
+
+	zone, ok := match(zones)
+	return zone, ok, nil
+}
+
+func (c *zoneCache) findByName(ctx context.Context, name string) (dnsSdk.Zone, bool, error) {
+	return c.find(ctx, func(zones []dnsSdk.Zone) (dnsSdk.Zone, bool) {
+		for _, zone := range zones {
+			if normalizeDomainName(zone.GetName()) == normalizeDomainName(name) {
+				return zone, true
+			}
+		}
+		return dnsSdk.Zone{}, false
+	})
+}
+
+func (c *zoneCache) findById(ctx context.Context, id string) (dnsSdk.Zone, bool, error) {
+	return c.find(ctx, func(zones []dnsSdk.Zone) (dnsSdk.Zone, bool) {
+		for _, zone := range zones {
+			if zone.GetId() == id {
+				return zone, true
+			}
+		}
+		return dnsSdk.Zone{}, false
+	})
+}
+
+func (c *zoneCache) findForRecordName(ctx context.Context, name string) (dnsSdk.Zone, bool, error) {
+	return c.find(ctx, func(zones []dnsSdk.Zone) (dnsSdk.Zone, bool) {
+		return findZoneForName(zones, name)
+	})
+}
+
+// resolveRecordZone finds the zone of a record from its zone_id, its zone_name or, if neither is set, from
+// the zone whose name is the longest suffix of the record name.
+func resolveRecordZone(ctx context.Context, zones *zoneCache, zoneId string, zoneName string, name string) (dnsSdk.Zone, error) {
+	var zone dnsSdk.Zone
+	var found bool
+	var err error
+	var description string
+
+	switch {
+	case zoneId != "":
+		zone, found, err = zones.findById(ctx, zoneId)
+		description = "with the ID " + zoneId
+	case zoneName != "":
+		zone, found, err = zones.findByName(ctx, zoneName)
+		description = "with the name " + zoneName
+	default:
+		zone, found, err = zones.findForRecordName(ctx, name)
+		description = "containing " + name
+	}
+
+	if err != nil {
+		return zone, fmt.Errorf("unable to get DNS zones: %v\n%s", err, getIndentedBody(err))
+	}
+	if !found {
+		return zone, fmt.Errorf("no DNS zone %s", description)
+	}
+
+	return zone, nil
+}
+
+// findZoneForName returns the zone that a record name belongs to. When zones are nested (e.g. example.com and
+// sub.example.com), the zone with the longest matching suffix wins.
+func findZoneForName(zones []dnsSdk.Zone, name string) (dnsSdk.Zone, bool) {
+	name = normalizeDomainName(name)
+
+	var found dnsSdk.Zone
+	matched := false
+	for _, zone := range zones {
+		zoneName := normalizeDomainName(zone.GetName())
+		if name != zoneName && !strings.HasSuffix(name, "."+zoneName) {
+			continue
+		}
+
+		if !matched || len(zoneName) > len(normalizeDomainName(found.GetName())) {
+			found = zone
+			matched = true
+		}
+	}
+
+	return found, matched
+}
+
+// expandRecordName expands a name relative to the zone, e.g. "www" or "@" for the apex, to a fully qualified
+// name. Names that end with a dot or with the name of the zone are already fully qualified.
+func expandRecordName(name string, zoneName string) string {
+	name = strings.TrimSpace(name)
+	zoneName = normalizeDomainName(zoneName)
+
+	switch {
+	case zoneName == "":
+		return name
+	case name == "@":
+		return zoneName
+	case strings.HasSuffix(name, "."):
+		return strings.TrimSuffix(name, ".")
+	case normalizeDomainName(name) == zoneName || strings.HasSuffix(normalizeDomainName(name), "."+zoneName):
+		return name
+	default:
+		return name + "." + zoneName
+	}
+}
+
+func normalizeDomainName(name string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(name), "."))
+}
@@ -0,0 +1,107 @@
+package ionosdeveloper
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	dnsSdk "github.com/ionos-developer/dns-sdk-go"
+)
+
+func TestFindZoneForName(t *testing.T) {
+	zones := []dnsSdk.Zone{
+		{Id: dnsSdk.PtrString("1"), Name: dnsSdk.PtrString("example.com")},
+		{Id: dnsSdk.PtrString("2"), Name: dnsSdk.PtrString("sub.example.com")},
+		{Id: dnsSdk.PtrString("3"), Name: dnsSdk.PtrString("ample.com")},
+	}
+
+	cases := []struct {
+		name     string
+		expected string
+	}{
+		{"example.com", "1"},
+		{"www.example.com.", "1"},
+		{"_acme-challenge.SUB.example.com", "2"},
+		{"sub.example.com", "2"},
+		{"ample.com", "3"},
+		{"example.org", ""},
+	}
+
+	for _, c := range cases {
+		zone, ok := findZoneForName(zones, c.name)
+		if ok != (c.expected != "") || zone.GetId() != c.expected {
+			t.Errorf("findZoneForName(%q): expected zone %q, got %q", c.name, c.expected, zone.GetId())
+		}
+	}
+}
+
+func TestResolveRecordZone(t *testing.T) {
+	api := newFakeDnsApi()
+	defer api.Close()
+
+	parentId := api.AddZone("example.com")
+	subId := api.AddZone("sub.example.com")
+	zones := newZoneCache(newDnsApiClient(api.URL(), defaultAuthHeader, fakeApiKey, "test", nil))
+
+	cases := []struct {
+		name     string
+		zoneId   string
+		zoneName string
+		record   string
+		expected string
+	}{
+		{name: "zone id", zoneId: subId, record: "www.example.com", expected: subId},
+		{name: "zone name", zoneName: "Example.com.", record: "www.sub.example.com", expected: parentId},
+		{name: "inferred", record: "www.sub.example.com", expected: subId},
+		{name: "inferred apex", record: "example.com", expected: parentId},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			zone, err := resolveRecordZone(context.Background(), zones, c.zoneId, c.zoneName, c.record)
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			if zone.GetId() != c.expected {
+				t.Errorf("expected zone %s, got %s", c.expected, zone.GetId())
+			}
+		})
+	}
+
+	if calls := api.Calls("GetZones"); calls != 1 {
+		t.Errorf("expected the zones to be requested once, got %d requests", calls)
+	}
+
+	// Zones created after the first lookup are found by reloading the zones
+	newId := api.AddZone("example.org")
+	if zone, err := resolveRecordZone(context.Background(), zones, "", "example.org", ""); err != nil || zone.GetId() != newId {
+		t.Errorf("expected zone %s, got %s (%v)", newId, zone.GetId(), err)
+	}
+
+	if _, err := resolveRecordZone(context.Background(), zones, "", "", "www.example.net"); err == nil || !strings.Contains(err.Error(), "no DNS zone containing www.example.net") {
+		t.Errorf("expected an error for an unknown zone, got %v", err)
+	}
+}
+
+func TestExpandRecordName(t *testing.T) {
+	cases := []struct {
+		name     string
+		zoneName string
+		expected string
+	}{
+		{"@", "example.com", "example.com"},
+		{"www", "example.com", "www.example.com"},
+		{"www.sub", "example.com.", "www.sub.example.com"},
+		{"www.example.com", "example.com", "www.example.com"},
+		{"WWW.Example.com", "example.com", "WWW.Example.com"},
+		{"example.com", "example.com", "example.com"},
+		{"www.example.org.", "example.com", "www.example.org"},
+		{"www", "", "www"},
+	}
+
+	for _, c := range cases {
+		if expanded := expandRecordName(c.name, c.zoneName); expanded != c.expected {
+			t.Errorf("expected %q in zone %q to expand to %q, got %q", c.name, c.zoneName, c.expected, expanded)
+		}
+	}
+}
@@ -1,16 +1,32 @@
 package main
 
 import (
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6/tf6server"
 
 	ionosdeveloper "github.com/ionos-developer/terraform-provider-ionosdeveloper/ionosdeveloper"
 )
 
 func main() {
-	plugin.Serve(&plugin.ServeOpts{
-		ProviderFunc: func() *schema.Provider {
-			return ionosdeveloper.Provider()
-		},
-	})
+	// Terraform starts the plugin without arguments, anything else is a command run by a user
+	if len(os.Args) > 1 && os.Args[1] == "generate" {
+		if err := ionosdeveloper.Generate(context.Background(), os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	serverFactory, err := ionosdeveloper.ProviderServerFactory(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := tf6server.Serve("registry.terraform.io/ionos-developer/ionosdeveloper", serverFactory); err != nil {
+		log.Fatal(err)
+	}
 }